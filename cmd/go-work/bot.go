@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rsilvagit/go-work/internal/bot"
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/server"
+)
+
+// runBot implements "go-work bot": answers /buscar commands on Telegram.
+func runBot(args []string) int {
+	fs := newFlagSet("bot", "bot [flags]",
+		"Bot do Telegram: responde a \"/buscar <termos> [| local]\" com as vagas encontradas.\n"+
+			"Só atende os chats de -telegram-chat-id; os filtros de vaga valem para todas as buscas.")
+	f := registerSearchFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	token := envOrFlag(*f.telegramToken, "TELEGRAM_TOKEN")
+	chats := f.telegramChats()
+	if token == "" || len(chats) == 0 {
+		fmt.Fprintln(os.Stderr, "Erro: o bot requer -telegram-token e -telegram-chat-id (ou TELEGRAM_TOKEN e TELEGRAM_CHAT_ID)")
+		return 1
+	}
+	if err := f.params().Filter.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+
+	cfg, err := f.loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	a, err := newApp(f, cfg, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	defer a.Close()
+
+	base, engineSearch := f.params(), server.EngineSearch(a.engine)
+	search := func(ctx context.Context, queries []string, location string) ([]model.Job, error) {
+		if location == "" {
+			location = base.Location
+		}
+		return engineSearch(ctx, server.SearchRequest{Queries: queries, Location: location, Filter: base.Filter})
+	}
+	b := bot.New(search, bot.Options{
+		Token:        token,
+		AllowedChats: chats,
+		Timeout:      a.timeout,
+		Logger:       a.log,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a.log.Info("bot: iniciado", "chats", len(chats))
+	if err := b.Run(ctx); err != nil {
+		a.log.Error("bot: falha", "err", err)
+		return 1
+	}
+	a.log.Info("bot: encerrado")
+	return 0
+}
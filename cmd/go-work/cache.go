@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/cache"
+	"github.com/rsilvagit/go-work/internal/store"
+)
+
+const cacheActions = `  list   lista as buscas em cache, com idade e TTL
+  purge  remove buscas do cache (exige -fonte, -q, -antigas ou -todas)
+  stats  resume o cache por fonte`
+
+const cacheUsage = "Uso: go-work cache <list|purge|stats> [flags]\n\n" + cacheActions
+
+// runCache implements "go-work cache": inspects and purges the result cache.
+func runCache(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" {
+		fmt.Fprintln(os.Stderr, cacheUsage)
+		return 1
+	}
+	action := args[0]
+
+	fs := newFlagSet("cache "+action, "cache "+action+" [flags]", cacheActions)
+	spec := fs.String("cache", "", "Cache de resultados: redis://host:6379 ou file:///dir (padrão: CACHE_URL ou REDIS_URL)")
+	source := fs.String("fonte", "", "Apenas buscas dessa fonte (ex: \"gupy\")")
+	query := fs.String("q", "", "Apenas buscas com esse termo")
+	outdated := fs.Bool("antigas", false, "Apenas entradas de outra versão do schema ou de chaves sem versão, que nunca são lidas")
+	all := fs.Bool("todas", false, "Confirma a remoção de todas as entradas (purge)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	url := envOrFlag(*spec, "CACHE_URL")
+	if url == "" {
+		url = os.Getenv("REDIS_URL")
+	}
+	if url == "" {
+		fmt.Fprintln(os.Stderr, "Erro: informe o cache com -cache, CACHE_URL ou REDIS_URL")
+		return 1
+	}
+
+	st, err := store.Open(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	c := cache.New(st, cache.Options{})
+	defer c.Close()
+
+	ctx := context.Background()
+	match := cache.Filter{Scraper: *source, Query: *query, Outdated: *outdated}
+
+	switch action {
+	case "list":
+		items, err := c.List(ctx, match)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return 1
+		}
+		printCacheItems(items)
+	case "purge":
+		if match == (cache.Filter{}) && !*all {
+			fmt.Fprintln(os.Stderr, "Erro: purge sem filtro remove tudo; use -todas para confirmar")
+			return 1
+		}
+		n, err := c.Purge(ctx, match)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d entrada(s) removida(s).\n", n)
+	case "stats":
+		items, err := c.List(ctx, match)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return 1
+		}
+		printCacheStats(items)
+	default:
+		fmt.Fprintf(os.Stderr, "Erro: ação desconhecida %q\n\n%s\n", action, cacheUsage)
+		return 1
+	}
+	return 0
+}
+
+// cacheState describes an entry for the listings.
+func cacheState(it cache.Item) string {
+	switch {
+	case it.Outdated:
+		return "versão antiga"
+	case it.Entry.Err != "":
+		return "falha"
+	case it.Entry.Stale():
+		return "vencida"
+	case len(it.Entry.Jobs) == 0:
+		return "vazia"
+	default:
+		return "fresca"
+	}
+}
+
+func printCacheItems(items []cache.Item) {
+	if len(items) == 0 {
+		fmt.Println("Nenhuma entrada no cache.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tBUSCA\tLOCAL\tVAGAS\tESTADO\tIDADE\tEXPIRA EM\tCHAVE")
+	fmt.Fprintln(w, "-----\t-----\t-----\t-----\t------\t-----\t---------\t-----")
+	for _, it := range items {
+		e := it.Entry
+		age := "-"
+		if !e.FetchedAt.IsZero() {
+			age = e.Age().Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			dash(e.Scraper), dash(e.Query), dash(e.Location), len(e.Jobs), cacheState(it),
+			age, time.Until(it.Expires).Round(time.Second), it.Key)
+	}
+	w.Flush()
+	fmt.Printf("\nTotal: %d entrada(s).\n", len(items))
+}
+
+func printCacheStats(items []cache.Item) {
+	if len(items) == 0 {
+		fmt.Println("Nenhuma entrada no cache.")
+		return
+	}
+
+	type row struct {
+		entries, jobs, size int
+		states              map[string]int
+	}
+	rows := make(map[string]*row)
+	for _, it := range items {
+		name := it.Entry.Scraper
+		if it.Outdated || name == "" {
+			name = "(versão antiga)"
+		}
+		r, ok := rows[name]
+		if !ok {
+			r = &row{states: make(map[string]int)}
+			rows[name] = r
+		}
+		r.entries++
+		r.jobs += len(it.Entry.Jobs)
+		r.size += it.Size
+		r.states[cacheState(it)]++
+	}
+	names := make([]string, 0, len(rows))
+	for name := range rows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tENTRADAS\tFRESCAS\tVENCIDAS\tVAZIAS\tFALHAS\tVAGAS\tTAMANHO")
+	fmt.Fprintln(w, "-----\t--------\t-------\t--------\t------\t------\t-----\t-------")
+	for _, name := range names {
+		r := rows[name]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%.1f KB\n",
+			name, r.entries, r.states["fresca"], r.states["vencida"], r.states["vazia"],
+			r.states["falha"], r.jobs, float64(r.size)/1024)
+	}
+	w.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rsilvagit/go-work/internal/config"
+)
+
+const configUsage = `Uso: go-work config validate [-config arquivo]
+
+  validate  verifica o arquivo de configuração sem executar nada`
+
+// runConfig implements "go-work config": checks the configuration file.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, configUsage)
+		return 1
+	}
+
+	fs := newFlagSet("config validate", "config validate [-config arquivo]",
+		"Verifica o arquivo de configuração: JSON, campos conhecidos, perfis e política por domínio.\n"+
+			"Todos os problemas encontrados são listados de uma vez.")
+	path := fs.String("config", "", "Arquivo JSON de configuração (padrão: GOWORK_CONFIG)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	file := envOrFlag(*path, "GOWORK_CONFIG")
+	if file == "" {
+		fmt.Fprintln(os.Stderr, "Erro: informe o arquivo com -config ou GOWORK_CONFIG")
+		return 1
+	}
+	cfg, err := config.Load(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s: ok (%d perfil(s), %d domínio(s))\n", file, len(cfg.Profiles), len(cfg.Domains))
+	return 0
+}
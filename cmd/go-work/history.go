@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/history"
+)
+
+// runHistory implements "go-work history": queries the SQLite job history.
+func runHistory(args []string) int {
+	fs := newFlagSet("history", "history [flags]",
+		"Consulta o histórico SQLite das vagas vistas nas buscas com -history-db.")
+	dbPath := fs.String("db", "", "Arquivo SQLite do histórico (padrão: HISTORY_DB ou \"go-work.db\")")
+	text := fs.String("q", "", "Texto para buscar no título ou empresa")
+	source := fs.String("fonte", "", "Filtrar por fonte (ex: \"gupy\")")
+	since := fs.Duration("desde", 0, "Apenas vagas vistas nesse período (ex: 168h)")
+	onlyOpen := fs.Bool("abertas", false, "Apenas vagas ainda abertas")
+	onlyClosed := fs.Bool("encerradas", false, "Apenas vagas encerradas")
+	limit := fs.Int("limit", 50, "Número máximo de vagas listadas (0 = sem limite)")
+	stats := fs.Bool("stats", false, "Exibe por fonte quanto tempo as vagas ficam abertas")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	path := envOrFlag(*dbPath, "HISTORY_DB")
+	if path == "" {
+		path = "go-work.db"
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	if *stats {
+		st, err := store.Stats(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return 1
+		}
+		printHistoryStats(st)
+		return 0
+	}
+
+	q := history.Query{
+		Text:   *text,
+		Source: *source,
+		Open:   *onlyOpen,
+		Closed: *onlyClosed,
+		Limit:  *limit,
+	}
+	if *since > 0 {
+		q.Since = time.Now().Add(-*since)
+	}
+
+	records, err := store.Find(ctx, q)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	printHistory(records)
+	return 0
+}
+
+func printHistory(records []history.Record) {
+	if len(records) == 0 {
+		fmt.Println("Nenhuma vaga no histórico.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tTITULO\tEMPRESA\tVISTA EM\tULTIMA VEZ\tENCERRADA\tURL")
+	fmt.Fprintln(w, "-----\t------\t-------\t--------\t----------\t---------\t---")
+	for _, r := range records {
+		closed := "-"
+		if !r.Open() {
+			closed = r.ClosedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Source, r.Title, r.Company,
+			r.FirstSeen.Format("2006-01-02"), r.LastSeen.Format("2006-01-02"),
+			closed, r.URL)
+	}
+	w.Flush()
+	fmt.Printf("\nTotal: %d vaga(s).\n", len(records))
+}
+
+func printHistoryStats(stats []history.SourceStats) {
+	if len(stats) == 0 {
+		fmt.Println("Nenhuma vaga no histórico.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tTOTAL\tABERTAS\tENCERRADAS\tMEDIA ABERTA\tMEDIANA\tMAXIMO")
+	fmt.Fprintln(w, "-----\t-----\t-------\t----------\t------------\t-------\t------")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			s.Source, s.Total, s.Open, s.Closed,
+			formatDays(s.Mean), formatDays(s.Median), formatDays(s.Max))
+	}
+	w.Flush()
+}
+
+// formatDays renders a duration as days with one decimal, or "-" when zero.
+func formatDays(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f dia(s)", d.Hours()/24)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logFlags holds the logging flags shared by every command.
+type logFlags struct {
+	level  *string
+	format *string
+	quiet  *bool
+}
+
+func registerLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		level:  fs.String("log-level", "", "Nível de log: debug, info, warn, error (padrão: LOG_LEVEL ou info)"),
+		format: fs.String("log-format", "", "Formato do log: text ou json (padrão: LOG_FORMAT ou text)"),
+		quiet:  fs.Bool("quiet", false, "Modo silencioso: apenas erros no log e apenas os resultados no stdout"),
+	}
+}
+
+// setup builds the logger and installs it as the slog default. Logs always
+// go to stderr, so stdout carries only the results.
+func (lf *logFlags) setup() (*slog.Logger, error) {
+	logger, err := newLogger(os.Stderr,
+		envOrFlag(*lf.level, "LOG_LEVEL"), envOrFlag(*lf.format, "LOG_FORMAT"), *lf.quiet)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+func newLogger(w io.Writer, level, format string, quiet bool) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "", "info":
+		lvl = slog.LevelInfo
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		return nil, fmt.Errorf("nível de log inválido %q (use debug, info, warn ou error)", level)
+	}
+	if quiet {
+		lvl = max(lvl, slog.LevelError)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("formato de log inválido %q (use text ou json)", format)
+	}
+}
@@ -2,20 +2,9 @@ package main
 
 import (
 	"bufio"
-	"context"
-	"flag"
 	"fmt"
 	"os"
 	"strings"
-	"sync"
-	"time"
-
-	"github.com/rsilvagit/go-work/internal/cache"
-	"github.com/rsilvagit/go-work/internal/filter"
-	"github.com/rsilvagit/go-work/internal/httpclient"
-	"github.com/rsilvagit/go-work/internal/model"
-	"github.com/rsilvagit/go-work/internal/output"
-	"github.com/rsilvagit/go-work/internal/scraper"
 )
 
 func loadEnv(path string) {
@@ -50,159 +39,63 @@ func envOrFlag(flagVal, envKey string) string {
 	return os.Getenv(envKey)
 }
 
-func main() {
-	loadEnv(".env")
-
-	query := flag.String("q", "", "Termo de busca (ex: \"golang developer\")")
-	location := flag.String("l", "", "Localização (ex: \"São Paulo\")")
-	timeout := flag.Duration("timeout", 30*time.Second, "Timeout por scraper")
-	telegramToken := flag.String("telegram-token", "", "Token do bot Telegram")
-	telegramChatID := flag.String("telegram-chat-id", "", "Chat ID do Telegram")
-	discordWebhook := flag.String("discord-webhook", "", "URL do Webhook Discord")
-	jobType := flag.String("tipo", "", "Tipo de vaga: full-time, part-time, estagio, freelance")
-	workModel := flag.String("modelo", "", "Modelo: remoto, hibrido, presencial")
-	level := flag.String("nivel", "", "Nível: junior, pleno, senior")
-	region := flag.String("regiao", "", "Região/cidade para filtrar (ex: \"São Paulo\")")
-	proxyURL := flag.String("proxy", "", "URL do proxy HTTP/HTTPS (ex: \"http://proxy:8080\")")
-	redisURL := flag.String("redis-url", "", "URL do Redis (ex: \"redis://localhost:6379\")")
-	cacheTTL := flag.Duration("cache-ttl", 1*time.Hour, "TTL do cache de resultados")
-	minDelay := flag.Duration("min-delay", 2*time.Second, "Delay mínimo entre requests ao mesmo domínio")
-	maxDelay := flag.Duration("max-delay", 5*time.Second, "Delay máximo entre requests ao mesmo domínio")
-	flag.Parse()
-
-	// Resolver flags com fallback para env vars.
-	q := envOrFlag(*query, "SEARCH_QUERY")
-	loc := envOrFlag(*location, "SEARCH_LOCATION")
-	jt := envOrFlag(*jobType, "SEARCH_TIPO")
-	wm := envOrFlag(*workModel, "SEARCH_MODELO")
-	lv := envOrFlag(*level, "SEARCH_NIVEL")
-	rg := envOrFlag(*region, "SEARCH_REGIAO")
-
-	if q == "" {
-		fmt.Fprintln(os.Stderr, "Erro: -q (query) ou SEARCH_QUERY é obrigatório")
-		flag.Usage()
-		os.Exit(1)
-	}
-
-	// Suporte a múltiplos termos separados por vírgula.
-	var queries []string
-	for _, term := range strings.Split(q, ",") {
-		term = strings.TrimSpace(term)
-		if term != "" {
-			queries = append(queries, term)
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
 		}
 	}
+	return items
+}
 
-	// HTTP client com proteções anti-ban.
-	httpClient, err := httpclient.New(httpclient.Options{
-		ProxyURL: envOrFlag(*proxyURL, "PROXY_URL"),
-		MinDelay: *minDelay,
-		MaxDelay: *maxDelay,
-	})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Erro ao criar HTTP client: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Cache Redis (opcional).
-	var jobCache *cache.Cache
-	rURL := envOrFlag(*redisURL, "REDIS_URL")
-	if rURL != "" {
-		jobCache, err = cache.New(rURL, *cacheTTL)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Aviso: Redis indisponível, continuando sem cache: %v\n", err)
-		} else {
-			defer jobCache.Close()
-		}
-	}
-
-	scrapers := scraper.Registry(httpClient)
-	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
-	defer cancel()
-
-	var (
-		mu      sync.Mutex
-		allJobs []model.Job
-		wg      sync.WaitGroup
-	)
-
-	for _, s := range scrapers {
-		for _, term := range queries {
-			wg.Add(1)
-			go func(s scraper.Scraper, term string) {
-				defer wg.Done()
-
-				// Verificar cache primeiro.
-				if jobCache != nil {
-					if cached, ok := jobCache.Get(ctx, s.Name(), term, loc); ok {
-						fmt.Printf("[cache hit] %s (%s): %d vaga(s) do cache\n", s.Name(), term, len(cached))
-						mu.Lock()
-						allJobs = append(allJobs, cached...)
-						mu.Unlock()
-						return
-					}
-				}
-
-				fmt.Printf("Buscando \"%s\" em %s...\n", term, s.Name())
-				jobs, err := s.Search(ctx, term, loc)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Aviso: %s (%s) falhou: %v\n", s.Name(), term, err)
-					return
-				}
-
-				// Salvar no cache.
-				if jobCache != nil && len(jobs) > 0 {
-					if err := jobCache.Set(ctx, s.Name(), term, loc, jobs); err != nil {
-						fmt.Fprintf(os.Stderr, "Aviso: falha ao salvar cache para %s (%s): %v\n", s.Name(), term, err)
-					}
-				}
+// command is a go-work subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
 
-				mu.Lock()
-				allJobs = append(allJobs, jobs...)
-				mu.Unlock()
-			}(s, term)
-		}
-	}
-	wg.Wait()
+var commands = []command{
+	{"search", "Busca vagas uma vez e envia aos canais configurados (padrão)", runSearch},
+	{"serve", "Executa as buscas agendadas e expõe a API HTTP", runServe},
+	{"bot", "Bot do Telegram que responde a /buscar", runBot},
+	{"cache", "Lista, resume e limpa o cache de resultados", runCache},
+	{"history", "Consulta o histórico de vagas", runHistory},
+	{"sources", "Lista as fontes de vagas disponíveis", runSources},
+	{"config", "Valida o arquivo de configuração (config validate)", runConfig},
+}
 
-	// Deduplicate jobs by URL (or title+company).
-	seen := make(map[string]bool)
-	var uniqueJobs []model.Job
-	for _, j := range allJobs {
-		key := j.Key()
-		if !seen[key] {
-			seen[key] = true
-			uniqueJobs = append(uniqueJobs, j)
-		}
+func usage() {
+	fmt.Fprintln(os.Stderr, "Uso: go-work [comando] [flags]")
+	fmt.Fprintln(os.Stderr, "\nComandos:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
 	}
+	fmt.Fprintln(os.Stderr, "\nSem comando, as flags vão para search (ex: go-work -q golang).")
+	fmt.Fprintln(os.Stderr, "Use \"go-work <comando> -h\" para ver as flags de cada comando.")
+}
 
-	// Apply filters.
-	uniqueJobs = filter.Apply(uniqueJobs, filter.Options{
-		JobType:   jt,
-		WorkModel: wm,
-		Level:     lv,
-		Region:    rg,
-	})
-
-	fmt.Println()
-	writers := []output.ResultWriter{output.NewConsolePrinter()}
-
-	tkn := envOrFlag(*telegramToken, "TELEGRAM_TOKEN")
-	chatID := envOrFlag(*telegramChatID, "TELEGRAM_CHAT_ID")
-	if tkn != "" && chatID != "" {
-		writers = append(writers, output.NewTelegramWriter(tkn, chatID))
-	}
+func main() {
+	loadEnv(".env")
 
-	dwURL := envOrFlag(*discordWebhook, "DISCORD_WEBHOOK_URL")
-	if dwURL != "" {
-		writers = append(writers, output.NewDiscordWriter(dwURL))
+	args := os.Args[1:]
+	switch {
+	case len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help"):
+		usage()
+		os.Exit(0)
+	case len(args) == 0 || strings.HasPrefix(args[0], "-"):
+		// Sem comando: compatível com "go-work -q golang".
+		os.Exit(runSearch(args))
 	}
-
-	for _, w := range writers {
-		if err := w.WriteJobs(uniqueJobs); err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao exibir resultados: %v\n", err)
+	for _, c := range commands {
+		if c.name == args[0] {
+			os.Exit(c.run(args[1:]))
 		}
 	}
-
-	fmt.Printf("\nTotal: %d vaga(s) encontrada(s).\n", len(uniqueJobs))
+	fmt.Fprintf(os.Stderr, "Erro: comando desconhecido %q\n\n", args[0])
+	usage()
+	os.Exit(1)
 }
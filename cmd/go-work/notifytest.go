@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/report"
+)
+
+// sampleJob is the posting sent by -notify-test.
+func sampleJob() model.Job {
+	return model.Job{
+		Title:       "Vaga de teste do go-work",
+		Company:     "go-work",
+		Location:    "Remoto",
+		Description: "Mensagem de teste enviada com -notify-test.",
+		URL:         "https://github.com/rsilvagit/go-work",
+		Source:      "go-work",
+		JobType:     "full-time",
+		WorkModel:   "remoto",
+		PostedAt:    time.Now(),
+	}
+}
+
+// notifyTest sends a sample job to every notification channel, so tokens
+// and webhooks can be checked without running a search. The console and
+// NDJSON outputs are skipped.
+func (a *app) notifyTest() int {
+	job := sampleJob()
+	sent, failed := 0, 0
+	for _, w := range a.writers {
+		var name string
+		switch w := w.(type) {
+		case *output.TelegramWriter:
+			name = "telegram (chat " + w.ChatID() + ")"
+		case *output.DiscordWriter:
+			name = "discord"
+		case *output.WebhookWriter:
+			name = "webhook"
+		default:
+			continue
+		}
+
+		sent++
+		if err := w.WriteJobs([]model.Job{job}); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: falhou: %v\n", name, err)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s: ok\n", name)
+	}
+
+	switch {
+	case sent == 0:
+		fmt.Fprintln(os.Stderr, "Erro: nenhum canal configurado (-telegram-token/-telegram-chat-id, -discord-webhook ou -webhook)")
+		return report.ExitUsage
+	case failed > 0:
+		return report.ExitWriterFailed
+	}
+	return report.ExitOK
+}
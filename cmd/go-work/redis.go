@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rsilvagit/go-work/internal/store"
+)
+
+// redisClients dials each Redis URL once, so the result cache, the HTTP
+// cache, the circuit breaker and the rate limiter share one client. A URL
+// that failed is not dialed again.
+type redisClients struct {
+	clients map[string]*redis.Client
+	errs    map[string]error
+}
+
+func newRedisClients() *redisClients {
+	return &redisClients{clients: make(map[string]*redis.Client), errs: make(map[string]error)}
+}
+
+// get returns the client of url, connecting on first use.
+func (rc *redisClients) get(url string) (*redis.Client, error) {
+	if c, ok := rc.clients[url]; ok {
+		return c, nil
+	}
+	if err, ok := rc.errs[url]; ok {
+		return nil, err
+	}
+	c, err := store.DialRedis(url)
+	if err != nil {
+		rc.errs[url] = err
+		return nil, err
+	}
+	rc.clients[url] = c
+	return c, nil
+}
+
+// open opens the store of spec, on the shared client for redis:// specs.
+func (rc *redisClients) open(spec string) (store.Store, error) {
+	if !store.IsRedis(spec) {
+		return store.Open(spec)
+	}
+	c, err := rc.get(spec)
+	if err != nil {
+		return nil, err
+	}
+	return store.NewRedis(c), nil
+}
+
+// Close closes every client.
+func (rc *redisClients) Close() {
+	for _, c := range rc.clients {
+		c.Close()
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/cache"
+	"github.com/rsilvagit/go-work/internal/config"
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/history"
+	"github.com/rsilvagit/go-work/internal/httpclient"
+	"github.com/rsilvagit/go-work/internal/metrics"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/pipeline"
+	"github.com/rsilvagit/go-work/internal/report"
+	"github.com/rsilvagit/go-work/internal/scraper"
+	"github.com/rsilvagit/go-work/internal/store"
+)
+
+// searchFlags holds the flags shared by every command that runs searches.
+type searchFlags struct {
+	*globalFlags
+	query          *string
+	location       *string
+	timeout        *time.Duration
+	queryTimeout   *time.Duration
+	concurrency    *int
+	perSource      *int
+	pages          *int
+	telegramToken  *string
+	telegramChatID *string
+	discordWebhook *string
+	webhookURL     *string
+	ndjsonPath     *string
+	jobType        *string
+	workModel      *string
+	level          *string
+	region         *string
+	state          *string
+	city           *string
+	cacheURL       *string
+	cacheTTL       *time.Duration
+	cacheStale     *time.Duration
+	cacheNegative  *time.Duration
+	minDelay       *time.Duration
+	maxDelay       *time.Duration
+	burst          *int
+	sharedLimit    *bool
+	breakerMax     *int
+	breakerCool    *time.Duration
+	breakerShared  *bool
+	historyDB      *string
+	recordDir      *string
+	replayDir      *string
+	httpCache      *string
+	ignoreRobots   *bool
+	detectClosed   *bool
+	confirmTimeout *time.Duration
+	notifyClosed   *bool
+	notifySummary  *bool
+	dryRun         *bool
+}
+
+func registerSearchFlags(fs *flag.FlagSet) *searchFlags {
+	return &searchFlags{
+		globalFlags:    registerGlobalFlags(fs),
+		query:          fs.String("q", "", "Termo de busca (ex: \"golang developer\")"),
+		location:       fs.String("l", "", "Localização (ex: \"São Paulo\")"),
+		timeout:        fs.Duration("timeout", 30*time.Second, "Timeout por scraper (soma de todos os termos da fonte)"),
+		queryTimeout:   fs.Duration("query-timeout", 0, "Timeout por termo de busca em cada scraper (0 = apenas -timeout)"),
+		concurrency:    fs.Int("concurrency", 4, "Máximo de buscas simultâneas, somando todas as fontes"),
+		perSource:      fs.Int("per-source", 2, "Máximo de buscas simultâneas na mesma fonte"),
+		pages:          fs.Int("pages", 1, "Máximo de páginas de resultados por busca em cada fonte; cada página é uma request a mais"),
+		telegramToken:  fs.String("telegram-token", "", "Token do bot Telegram"),
+		telegramChatID: fs.String("telegram-chat-id", "", "Chat IDs do Telegram, separados por vírgula (ex: \"123456,-100987654\")"),
+		discordWebhook: fs.String("discord-webhook", "", "URL do Webhook Discord"),
+		webhookURL:     fs.String("webhook", "", "URL que recebe as vagas em JSON via POST"),
+		ndjsonPath:     fs.String("ndjson", "", "Grava as vagas em NDJSON nesse arquivo (\"-\" = stdout, no lugar da tabela)"),
+		jobType:        fs.String("tipo", "", "Tipo de vaga: full-time, part-time, estagio, freelance"),
+		workModel:      fs.String("modelo", "", "Modelo: remoto, hibrido, presencial"),
+		level:          fs.String("nivel", "", "Nível: junior, pleno, senior"),
+		region:         fs.String("regiao", "", "Região/cidade para filtrar (ex: \"São Paulo\")"),
+		state:          fs.String("uf", "", "UFs da vaga, por sigla ou nome (ex: \"SP,RJ\")"),
+		city:           fs.String("cidade", "", "Municípios da vaga, sem diferenciar acentos (ex: \"Campinas\")"),
+		cacheURL:       fs.String("cache", "", "Cache de resultados: redis://host:6379, mem://[?bytes=64MB&size=N] ou file:///dir (padrão: -redis-url)"),
+		cacheTTL:       fs.Duration("cache-ttl", 1*time.Hour, "TTL do cache de resultados"),
+		cacheStale:     fs.Duration("cache-stale-ttl", 24*time.Hour, "Tempo, após o TTL, em que o resultado vencido ainda é usado se a fonte falhar (negativo desativa)"),
+		cacheNegative:  fs.Duration("cache-negative-ttl", 5*time.Minute, "TTL de buscas vazias ou que falharam"),
+		minDelay:       fs.Duration("min-delay", 2*time.Second, "Delay mínimo entre requests ao mesmo domínio"),
+		maxDelay:       fs.Duration("max-delay", 5*time.Second, "Delay máximo entre requests ao mesmo domínio"),
+		burst:          fs.Int("burst", 1, "Requests seguidas ao mesmo domínio antes de aplicar o delay"),
+		sharedLimit:    fs.Bool("rate-limit-shared", false, "Compartilha o rate limit por domínio entre instâncias via Redis (requer -redis-url)"),
+		breakerMax:     fs.Int("breaker-threshold", 5, "Falhas seguidas que abrem o circuito de um host (negativo desativa)"),
+		breakerCool:    fs.Duration("breaker-cooldown", time.Minute, "Tempo que um host com circuito aberto fica sem receber requests"),
+		breakerShared:  fs.Bool("breaker-shared", false, "Compartilha o estado do circuit breaker via Redis (requer -redis-url)"),
+		recordDir:      fs.String("record", "", "Grava todas as requests e respostas HTTP nesse diretório"),
+		replayDir:      fs.String("replay", "", "Responde as requests com as gravações desse diretório, sem acessar a rede"),
+		httpCache:      fs.String("http-cache", "", "Cache HTTP com revalidação por ETag: mem://, file:///dir ou redis://host:6379"),
+		ignoreRobots:   fs.Bool("ignore-robots", false, "Não consulta o robots.txt nem respeita o Crawl-delay"),
+		historyDB:      fs.String("history-db", "", "Arquivo SQLite com o histórico de vagas (ex: \"go-work.db\")"),
+		detectClosed:   fs.Bool("detect-closed", false, "Detecta vagas encerradas (requer -history-db)"),
+		confirmTimeout: fs.Duration("confirm-timeout", 30*time.Second, "Prazo total para conferir as URLs das vagas sumidas (-detect-closed)"),
+		notifyClosed:   fs.Bool("notify-closed", false, "Envia o resumo de vagas encerradas aos canais configurados"),
+		notifySummary:  fs.Bool("notify-summary", false, "Envia o resumo da execução aos canais configurados"),
+		dryRun:         fs.Bool("dry-run", false, "Mostra no stdout as mensagens de Telegram, Discord e webhook em vez de enviá-las"),
+	}
+}
+
+// telegramChats returns the chats of -telegram-chat-id or TELEGRAM_CHAT_ID,
+// a comma-separated list.
+func (f *searchFlags) telegramChats() []string {
+	return splitList(envOrFlag(*f.telegramChatID, "TELEGRAM_CHAT_ID"))
+}
+
+// params resolves the search parameters, falling back to env vars.
+func (f *searchFlags) params() pipeline.SearchRequest {
+	return pipeline.SearchRequest{
+		Queries:  splitList(envOrFlag(*f.query, "SEARCH_QUERY")),
+		Location: envOrFlag(*f.location, "SEARCH_LOCATION"),
+		Filter: filter.Options{
+			JobType:   envOrFlag(*f.jobType, "SEARCH_TIPO"),
+			WorkModel: envOrFlag(*f.workModel, "SEARCH_MODELO"),
+			Level:     envOrFlag(*f.level, "SEARCH_NIVEL"),
+			Region:    envOrFlag(*f.region, "SEARCH_REGIAO"),
+			State:     envOrFlag(*f.state, "SEARCH_UF"),
+			City:      envOrFlag(*f.city, "SEARCH_CIDADE"),
+		},
+		DetectClosed:  *f.detectClosed,
+		NotifyClosed:  *f.notifyClosed,
+		NotifySummary: *f.notifySummary,
+		Notify:        true,
+	}
+}
+
+// app holds the long-lived dependencies shared by every search run.
+type app struct {
+	engine     *pipeline.Engine
+	writers    []output.ResultWriter
+	httpClient *httpclient.Client
+	cache      cache.Cache
+	history    *history.Store
+	ndjsonFile *os.File
+	redis      *redisClients
+	httpCache  store.Store
+	timeout    time.Duration // prazo de cada scraper
+	log        *slog.Logger
+}
+
+// newApp wires the HTTP client, optional cache and history, scrapers and
+// writers into a search engine. cfg may be nil. With background, stale cache
+// entries are served at once and refreshed in the background, for commands
+// that stay resident.
+func newApp(f *searchFlags, cfg *config.Config, background bool) (*app, error) {
+	logger, err := f.log.setup()
+	if err != nil {
+		return nil, err
+	}
+
+	proxies := splitList(envOrFlag(*f.proxyURL, "PROXY_URL"))
+	if path := envOrFlag(*f.proxyFile, "PROXY_FILE"); path != "" {
+		fromFile, err := httpclient.LoadProxyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, fromFile...)
+	}
+
+	opts := httpclient.Options{
+		Proxies:          proxies,
+		ProxyRotation:    *f.proxyRotation,
+		ProxyCooldown:    *f.proxyCooldown,
+		MinDelay:         *f.minDelay,
+		MaxDelay:         *f.maxDelay,
+		Burst:            *f.burst,
+		Logger:           logger,
+		BreakerThreshold: *f.breakerMax,
+		BreakerCooldown:  *f.breakerCool,
+		IgnoreRobots:     *f.ignoreRobots,
+		RecordDir:        *f.recordDir,
+		ReplayDir:        *f.replayDir,
+	}
+	if cfg != nil {
+		opts.Domains = make(map[string]httpclient.DomainPolicy, len(cfg.Domains))
+		for name, d := range cfg.Domains {
+			opts.Domains[strings.ToLower(name)] = httpclient.DomainPolicy{
+				MinDelay:     time.Duration(d.MinDelay),
+				MaxDelay:     time.Duration(d.MaxDelay),
+				Burst:        d.Burst,
+				IgnoreRobots: d.IgnoreRobots,
+			}
+		}
+	}
+
+	// Um único client por URL do Redis, compartilhado por breaker, rate
+	// limit e caches.
+	redisURL := envOrFlag(*f.redisURL, "REDIS_URL")
+	conns := newRedisClients()
+
+	// Circuit breaker compartilhado entre execuções (opcional).
+	if *f.breakerShared {
+		if redisURL == "" {
+			logger.Warn("-breaker-shared requer -redis-url, ignorando")
+		} else if rdb, err := conns.get(redisURL); err != nil {
+			logger.Warn("Redis indisponível, circuit breaker apenas local", "err", err)
+		} else {
+			opts.BreakerStore = httpclient.NewRedisBreakerStore(rdb)
+		}
+	}
+
+	// Rate limit compartilhado entre instâncias (opcional).
+	if *f.sharedLimit {
+		if redisURL == "" {
+			logger.Warn("-rate-limit-shared requer -redis-url, ignorando")
+		} else if rdb, err := conns.get(redisURL); err != nil {
+			logger.Warn("Redis indisponível, rate limit apenas local", "err", err)
+		} else {
+			opts.Limiter = httpclient.NewRedisLimiter(rdb)
+		}
+	}
+
+	// Cache HTTP das respostas (opcional).
+	var httpCache store.Store
+	if spec := envOrFlag(*f.httpCache, "HTTP_CACHE"); spec != "" {
+		if httpCache, err = conns.open(spec); err != nil {
+			logger.Warn("cache HTTP indisponível, seguindo sem ele", "err", err)
+			httpCache = nil
+		} else {
+			opts.HTTPCache = httpCache
+		}
+	}
+
+	// HTTP client com proteções anti-ban.
+	httpClient, err := httpclient.New(opts)
+	if err != nil {
+		if httpCache != nil {
+			httpCache.Close()
+		}
+		conns.Close()
+		return nil, fmt.Errorf("criando HTTP client: %w", err)
+	}
+
+	a := &app{
+		httpClient: httpClient,
+		redis:      conns,
+		httpCache:  httpCache,
+		timeout:    *f.timeout,
+		log:        logger,
+	}
+
+	// Cache de resultados (opcional): -cache, ou o Redis de -redis-url.
+	spec := envOrFlag(*f.cacheURL, "CACHE_URL")
+	if spec == "" {
+		spec = redisURL
+	}
+	if spec != "" {
+		if st, err := conns.open(spec); err != nil {
+			logger.Warn("cache indisponível, continuando sem cache", "err", err)
+		} else {
+			a.cache = cache.New(st, cache.Options{
+				TTL:         *f.cacheTTL,
+				StaleTTL:    *f.cacheStale,
+				NegativeTTL: *f.cacheNegative,
+				Pages:       *f.pages,
+			})
+		}
+	}
+
+	// Histórico SQLite (opcional).
+	if path := envOrFlag(*f.historyDB, "HISTORY_DB"); path != "" {
+		a.history, err = history.Open(path)
+		if err != nil {
+			logger.Warn("histórico indisponível, continuando sem histórico", "err", err)
+			a.history = nil
+		}
+	}
+
+	// Com -ndjson -, o NDJSON substitui a tabela no stdout.
+	var writers []output.ResultWriter
+	switch path := envOrFlag(*f.ndjsonPath, "NDJSON_PATH"); path {
+	case "":
+		writers = []output.ResultWriter{output.NewConsolePrinter()}
+	case "-":
+		writers = []output.ResultWriter{output.NewNDJSONWriter(os.Stdout)}
+	default:
+		a.ndjsonFile, err = os.Create(path)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("criando arquivo NDJSON: %w", err)
+		}
+		writers = []output.ResultWriter{output.NewConsolePrinter(), output.NewNDJSONWriter(a.ndjsonFile)}
+	}
+
+	if tkn := envOrFlag(*f.telegramToken, "TELEGRAM_TOKEN"); tkn != "" {
+		// Um writer por chat: cada um recebe as mensagens e falha sozinho.
+		for _, chatID := range f.telegramChats() {
+			tw := output.NewTelegramWriter(tkn, chatID)
+			if *f.dryRun {
+				tw.DryRun(os.Stdout)
+			}
+			writers = append(writers, tw)
+		}
+	}
+
+	if dwURL := envOrFlag(*f.discordWebhook, "DISCORD_WEBHOOK_URL"); dwURL != "" {
+		dw := output.NewDiscordWriter(dwURL)
+		if *f.dryRun {
+			dw.DryRun(os.Stdout)
+		}
+		writers = append(writers, dw)
+	}
+
+	if whURL := envOrFlag(*f.webhookURL, "WEBHOOK_URL"); whURL != "" {
+		ww := output.NewWebhookWriter(whURL)
+		if *f.dryRun {
+			ww.DryRun(os.Stdout)
+		}
+		writers = append(writers, ww)
+	}
+	a.writers = writers
+
+	engineOpts := pipeline.Options{
+		Scrapers:     scraper.Registry(httpClient, scraper.Options{Pages: *f.pages}),
+		Cache:        a.cache,
+		Client:       httpClient,
+		Writers:      writers,
+		Timeout:      *f.timeout,
+		QueryTimeout: *f.queryTimeout,
+		Concurrency:  *f.concurrency,
+		PerSource:    *f.perSource,
+		Background:   background,
+		Quiet:        *f.log.quiet,
+		Logger:       logger,
+
+		ConfirmTimeout: *f.confirmTimeout,
+	}
+	if a.history != nil {
+		engineOpts.History = a.history
+	}
+	a.engine = pipeline.New(engineOpts)
+	return a, nil
+}
+
+// Close releases the caches, history, Redis connections and the NDJSON
+// file.
+func (a *app) Close() {
+	if a.engine != nil {
+		a.engine.Close()
+	}
+	if a.httpCache != nil {
+		a.httpCache.Close()
+	}
+	if a.ndjsonFile != nil {
+		a.ndjsonFile.Close()
+	}
+	if a.cache != nil {
+		a.cache.Close()
+	}
+	if a.redis != nil {
+		a.redis.Close()
+	}
+	if a.history != nil {
+		a.history.Close()
+	}
+}
+
+// runSearch implements the default one-shot mode: search once and exit.
+func runSearch(args []string) int {
+	fs := newFlagSet("search", "[search] [flags]",
+		"Busca vagas em todas as fontes, aplica os filtros e envia o resultado aos canais configurados.\n"+
+			"É o comando padrão: \"go-work -q golang\" equivale a \"go-work search -q golang\".")
+	f := registerSearchFlags(fs)
+	metricsPush := fs.String("metrics-push", "", "URL do Prometheus Pushgateway para enviar as métricas ao final (padrão: METRICS_PUSH_URL)")
+	stream := fs.Bool("stream", false, "Mostra as vagas assim que cada página chega, em vez de esperar todas as buscas")
+	notifyTest := fs.Bool("notify-test", false, "Envia uma vaga de exemplo a cada canal configurado (Telegram, Discord, webhook) e sai, sem buscar")
+	explain := fs.Bool("explain", false, "Mostra, para cada vaga descartada, o filtro que a rejeitou e o texto verificado")
+	metricsFile := fs.String("metrics-file", "", "Grava as métricas no formato Prometheus nesse arquivo ao final (\"-\" = stdout)")
+	if err := fs.Parse(args); err != nil {
+		return report.ExitUsage
+	}
+
+	p := f.params()
+	p.Stream = *stream
+	p.Explain = *explain
+	if len(p.Queries) == 0 && !*notifyTest {
+		fmt.Fprintln(os.Stderr, "Erro: -q (query) ou SEARCH_QUERY é obrigatório")
+		fs.Usage()
+		return report.ExitUsage
+	}
+	if err := p.Filter.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return report.ExitUsage
+	}
+
+	cfg, err := f.loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return report.ExitUsage
+	}
+
+	a, err := newApp(f, cfg, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return report.ExitUsage
+	}
+	defer a.Close()
+
+	if *notifyTest {
+		return a.notifyTest()
+	}
+
+	// Se todas as buscas falharam, o resumo já traz o erro e o código de saída.
+	res, _ := a.engine.Run(context.Background(), p)
+	summary := res.Summary
+
+	if url := envOrFlag(*metricsPush, "METRICS_PUSH_URL"); url != "" {
+		if err := metrics.Push(url, "go-work"); err != nil {
+			a.log.Warn("falha ao enviar métricas", "err", err)
+		}
+	}
+	if *metricsFile != "" {
+		if err := metrics.Dump(*metricsFile); err != nil {
+			a.log.Warn("falha ao gravar métricas", "err", err)
+		}
+	}
+	return summary.ExitCode()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/config"
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/metrics"
+	"github.com/rsilvagit/go-work/internal/pipeline"
+	"github.com/rsilvagit/go-work/internal/scheduler"
+	"github.com/rsilvagit/go-work/internal/server"
+)
+
+// runServe implements "go-work serve": stays resident and runs each profile
+// on its schedule, reusing one HTTP client (and its per-host rate limit
+// state) across runs. With -addr it also serves the search as an HTTP API.
+func runServe(args []string) int {
+	fs := newFlagSet("serve", "serve [flags]",
+		"Fica residente executando os perfis agendados (flags de busca ou seção profiles do -config)\n"+
+			"e, com -addr, expõe a API HTTP com /jobs, /sources, /healthz e /metrics.")
+	f := registerSearchFlags(fs)
+	schedule := fs.String("schedule", "", "Agenda do perfil padrão: cron (\"0 12 * * *\") ou \"@every 6h\" (padrão: SEARCH_SCHEDULE ou \"0 12 * * *\")")
+	jitter := fs.Duration("jitter", 0, "Atraso aleatório máximo somado a cada execução")
+	runOnStart := fs.Bool("run-on-start", false, "Executa todos os perfis uma vez ao iniciar")
+	addr := fs.String("addr", "", "Endereço da API HTTP (ex: \":8080\"); vazio desativa (padrão: HTTP_ADDR)")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	httpAddr := envOrFlag(*addr, "HTTP_ADDR")
+	cfg, err := f.loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	profiles, err := serveProfiles(f, cfg, *schedule, *jitter, httpAddr != "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+
+	a, err := newApp(f, cfg, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return 1
+	}
+	defer a.Close()
+
+	sched := scheduler.New()
+	for _, p := range profiles {
+		s, _ := scheduler.Parse(p.Schedule) // validado em serveProfiles
+		params := profileParams(f, p)
+		name := p.Name
+		sched.Add(scheduler.Job{
+			Name:       name,
+			Schedule:   s,
+			Jitter:     time.Duration(p.Jitter),
+			RunOnStart: *runOnStart,
+			Run: func(ctx context.Context) {
+				a.log.Info("serve: executando perfil", "profile", name)
+				a.engine.Run(ctx, params)
+			},
+		})
+		a.log.Info("serve: perfil agendado", "profile", name, "schedule", p.Schedule,
+			"next", s.Next(time.Now()).Format(time.RFC3339))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 2)
+	)
+	if len(profiles) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- sched.Run(ctx)
+		}()
+	}
+	if httpAddr != "" {
+		srv := server.New(server.EngineSearch(a.engine), server.Options{
+			Addr:    httpAddr,
+			Timeout: a.timeout,
+			Sources: a.engine.Sources(),
+			Metrics: metrics.Handler(),
+		})
+		a.log.Info("serve: API HTTP iniciada", "addr", httpAddr)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.ListenAndServe(ctx); err != nil {
+				errs <- err
+				stop()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	code := 0
+	for err := range errs {
+		if err != nil {
+			a.log.Error("serve: falha", "err", err)
+			code = 1
+		}
+	}
+	a.log.Info("serve: encerrado")
+	return code
+}
+
+// serveProfiles returns the profiles from the config file, or a single
+// "default" profile built from the search flags when the file defines none.
+// With allowEmpty (API enabled), no query and no profiles means no profiles.
+func serveProfiles(f *searchFlags, cfg *config.Config, schedule string, jitter time.Duration, allowEmpty bool) ([]config.Profile, error) {
+	if cfg != nil && len(cfg.Profiles) > 0 {
+		return cfg.Profiles, nil
+	}
+
+	p := f.params()
+	if len(p.Queries) == 0 {
+		if allowEmpty {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("-q (query), SEARCH_QUERY, -config ou -addr é obrigatório")
+	}
+
+	schedule = envOrFlag(schedule, "SEARCH_SCHEDULE")
+	if schedule == "" {
+		schedule = "0 12 * * *"
+	}
+
+	fallback := config.Config{Profiles: []config.Profile{{
+		Name:     "default",
+		Schedule: schedule,
+		Jitter:   config.Duration(jitter),
+		Query:    envOrFlag(*f.query, "SEARCH_QUERY"),
+		Location: p.Location,
+		Tipo:     p.Filter.JobType,
+		Modelo:   p.Filter.WorkModel,
+		Nivel:    p.Filter.Level,
+		Regiao:   p.Filter.Region,
+		UF:       p.Filter.State,
+		Cidade:   p.Filter.City,
+	}}}
+	if err := fallback.Validate(); err != nil {
+		return nil, err
+	}
+	return fallback.Profiles, nil
+}
+
+// profileParams builds the search parameters for a profile. Options that
+// are not part of a profile, like -detect-closed, come from the flags.
+func profileParams(f *searchFlags, p config.Profile) pipeline.SearchRequest {
+	params := f.params()
+	params.Queries = splitList(p.Query)
+	params.Location = p.Location
+	params.Filter = filter.Options{
+		JobType:   p.Tipo,
+		WorkModel: p.Modelo,
+		Level:     p.Nivel,
+		Region:    p.Regiao,
+		State:     p.UF,
+		City:      p.Cidade,
+	}
+	return params
+}
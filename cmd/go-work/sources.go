@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rsilvagit/go-work/internal/scraper"
+)
+
+// runSources implements "go-work sources": lists the job sources.
+func runSources(args []string) int {
+	fs := newFlagSet("sources", "sources", "Lista as fontes de vagas disponíveis.")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tSTREAMING")
+	fmt.Fprintln(w, "-----\t---------")
+	for _, s := range scraper.Registry(nil, scraper.Options{}) {
+		streaming := "não"
+		if _, ok := s.(scraper.Streamer); ok {
+			streaming = "sim"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", s.Name(), streaming)
+	}
+	w.Flush()
+	return 0
+}
@@ -0,0 +1,237 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/output"
+)
+
+const (
+	apiURL      = "https://api.telegram.org"
+	pollTimeout = 50 * time.Second // long polling do getUpdates
+	retryDelay  = 5 * time.Second  // após falha no getUpdates
+)
+
+const helpText = `Comandos:
+
+/buscar <termos> — busca vagas, ex: /buscar golang, rust
+/buscar <termos> | <local> — com localização, ex: /buscar golang | São Paulo
+/ajuda — esta mensagem
+
+Os filtros (tipo, modelo, nível, região, UF, cidade) são os configurados no go-work.`
+
+// SearchFunc runs the scraper fan-out, dedup and filter pipeline.
+type SearchFunc func(ctx context.Context, queries []string, location string) ([]model.Job, error)
+
+// Options configures the Telegram bot.
+type Options struct {
+	Token string
+	// AllowedChats are the chat IDs the bot answers; messages from any
+	// other chat are refused, since each search hits the job sources.
+	AllowedChats []string
+	Timeout      time.Duration // deadline for each search (default 60s)
+	Logger       *slog.Logger
+}
+
+func (o Options) withDefaults() Options {
+	if o.Timeout == 0 {
+		o.Timeout = 60 * time.Second
+	}
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+	return o
+}
+
+// Bot answers /buscar commands sent to a Telegram bot, polling the Bot API
+// for updates.
+type Bot struct {
+	search  SearchFunc
+	opts    Options
+	allowed map[string]bool
+	client  *http.Client
+}
+
+// New creates a Bot that answers /buscar with search.
+func New(search SearchFunc, opts Options) *Bot {
+	b := &Bot{
+		search:  search,
+		opts:    opts.withDefaults(),
+		allowed: make(map[string]bool, len(opts.AllowedChats)),
+		client:  &http.Client{Timeout: pollTimeout + 10*time.Second},
+	}
+	for _, id := range opts.AllowedChats {
+		b.allowed[id] = true
+	}
+	return b
+}
+
+type update struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// Run polls for updates until ctx is canceled. Commands are answered one
+// at a time, in the order they arrive.
+func (b *Bot) Run(ctx context.Context) error {
+	var offset int64
+	for {
+		updates, err := b.updates(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			b.opts.Logger.Warn("bot: falha ao buscar mensagens", "err", err)
+			select {
+			case <-time.After(retryDelay):
+				continue
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+			b.handle(ctx, strconv.FormatInt(u.Message.Chat.ID, 10), u.Message.Text)
+		}
+	}
+}
+
+func (b *Bot) updates(ctx context.Context, offset int64) ([]update, error) {
+	q := url.Values{}
+	q.Set("timeout", strconv.Itoa(int(pollTimeout.Seconds())))
+	q.Set("offset", strconv.FormatInt(offset, 10))
+	q.Set("allowed_updates", `["message"]`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.method("getUpdates")+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("bot: building request: %w", err)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("bot: getUpdates: %w", stripURL(err))
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK          bool     `json:"ok"`
+		Description string   `json:"description"`
+		Result      []update `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("bot: decoding getUpdates: %w", err)
+	}
+	if !body.OK {
+		return nil, fmt.Errorf("bot: API error %d: %s", resp.StatusCode, body.Description)
+	}
+	return body.Result, nil
+}
+
+// handle answers one message.
+func (b *Bot) handle(ctx context.Context, chatID, text string) {
+	log := b.opts.Logger.With("chat", chatID)
+
+	command, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	command, _, _ = strings.Cut(command, "@") // "/buscar@meu_bot" em grupos
+	if !strings.HasPrefix(command, "/") {
+		return
+	}
+	if !b.allowed[chatID] {
+		log.Warn("bot: chat não autorizado", "command", command)
+		b.reply(ctx, chatID, "Chat não autorizado. Inclua o ID "+chatID+" em -telegram-chat-id para usar o bot.")
+		return
+	}
+
+	switch command {
+	case "/start", "/ajuda", "/help":
+		b.reply(ctx, chatID, helpText)
+	case "/buscar":
+		terms, location, _ := strings.Cut(args, "|")
+		queries := splitTerms(terms)
+		if len(queries) == 0 {
+			b.reply(ctx, chatID, "Informe o que buscar, ex: /buscar golang")
+			return
+		}
+		location = strings.TrimSpace(location)
+
+		log.Info("bot: buscando", "queries", queries, "location", location)
+		b.reply(ctx, chatID, "Buscando "+strings.Join(queries, ", ")+"…")
+
+		searchCtx, cancel := context.WithTimeout(ctx, b.opts.Timeout)
+		jobs, err := b.search(searchCtx, queries, location)
+		cancel()
+		if err != nil {
+			log.Warn("bot: busca falhou", "err", err)
+			b.reply(ctx, chatID, "A busca falhou: "+err.Error())
+			return
+		}
+		if err := output.NewTelegramWriter(b.opts.Token, chatID).WriteJobs(jobs); err != nil {
+			log.Warn("bot: falha ao enviar vagas", "err", err)
+		}
+	default:
+		b.reply(ctx, chatID, "Comando desconhecido.\n\n"+helpText)
+	}
+}
+
+// reply sends a plain-text message.
+func (b *Bot) reply(ctx context.Context, chatID, text string) {
+	body, _ := json.Marshal(map[string]string{"chat_id": chatID, "text": text})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.method("sendMessage"), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		b.opts.Logger.Warn("bot: falha ao responder", "chat", chatID, "err", stripURL(err))
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b.opts.Logger.Warn("bot: falha ao responder", "chat", chatID, "status", resp.StatusCode)
+	}
+}
+
+func (b *Bot) method(name string) string {
+	return apiURL + "/bot" + b.opts.Token + "/" + name
+}
+
+// stripURL drops the request URL from a client error, since it carries the
+// bot token.
+func stripURL(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return uerr.Err
+	}
+	return err
+}
+
+func splitTerms(s string) []string {
+	var terms []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
@@ -5,76 +5,270 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/store"
 )
 
-// Cache provides Redis-backed caching for scraped job results.
-type Cache struct {
-	client *redis.Client
-	ttl    time.Duration
+// Cache stores scraped job results per scraper/query/location.
+//
+// Entries have a soft and a hard TTL: after Options.TTL they are stale, and
+// Lookup still returns them (flagged) for Options.StaleTTL more, so callers
+// can serve old results when the source is failing. Empty results and
+// failures are cached too, for Options.NegativeTTL.
+type Cache interface {
+	// Get retrieves fresh cached jobs for the given scraper/query/location
+	// combination. Returns the jobs and true if a fresh, non-empty entry
+	// exists, or nil and false otherwise.
+	Get(ctx context.Context, scraper, query, location string) ([]model.Job, bool)
+	// Set stores jobs in the cache; an empty slice is a negative entry.
+	Set(ctx context.Context, scraper, query, location string, jobs []model.Job) error
+	// Lookup returns any entry still within its hard TTL: fresh, stale or
+	// negative.
+	Lookup(ctx context.Context, scraper, query, location string) (Entry, bool)
+	// SetError caches a failed search, so it is not retried on every run.
+	SetError(ctx context.Context, scraper, query, location string, err error) error
+	// List returns the stored entries matching f, in key order.
+	List(ctx context.Context, f Filter) ([]Item, error)
+	// Purge deletes the entries matching f and returns how many were removed.
+	Purge(ctx context.Context, f Filter) (int, error)
+	// Close releases the backend.
+	Close() error
 }
 
-// New connects to Redis at the given URL and returns a Cache.
-// URL format: redis://localhost:6379
-func New(redisURL string, ttl time.Duration) (*Cache, error) {
-	opts, err := redis.ParseURL(redisURL)
-	if err != nil {
-		return nil, fmt.Errorf("cache: invalid redis URL: %w", err)
+// Options configures the entry lifetimes.
+type Options struct {
+	TTL         time.Duration // entrada fresca (padrão 1h)
+	StaleTTL    time.Duration // tempo extra servindo a entrada vencida (padrão 24h)
+	NegativeTTL time.Duration // resultado vazio ou falha (padrão 5m)
+	// Pages is the page limit of the searches (scraper.Options.Pages). It
+	// is part of the key, so results fetched with fewer pages are a miss.
+	Pages int
+}
+
+func (o Options) withDefaults() Options {
+	if o.TTL <= 0 {
+		o.TTL = time.Hour
+	}
+	if o.StaleTTL < 0 {
+		o.StaleTTL = 0
+	} else if o.StaleTTL == 0 {
+		o.StaleTTL = 24 * time.Hour
 	}
+	if o.NegativeTTL <= 0 {
+		o.NegativeTTL = 5 * time.Minute
+	}
+	if o.Pages <= 0 {
+		o.Pages = 1
+	}
+	return o
+}
 
-	client := redis.NewClient(opts)
+// keyPrefix namespaces job cache keys; the version segment makes entries
+// of another model.SchemaVersion unreachable.
+const keyPrefix = "gowork:jobs:"
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// legacyPattern matches the keys of builds before keyPrefix,
+// gowork:<scraper>:<first 8 bytes of the sha256 in hex>. They are never
+// read, only listed as outdated so they can be purged.
+var legacyPattern = "gowork:*:" + strings.Repeat("[0-9a-f]", 16)
 
-	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("cache: redis ping failed: %w", err)
+// legacyScraper returns the scraper of a legacy key, and false for keys of
+// the other gowork namespaces that legacyPattern also matches.
+func legacyScraper(key string) (string, bool) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 3 || parts[0] != "gowork" || len(parts[2]) != 16 {
+		return "", false
 	}
+	switch parts[1] {
+	case "jobs", "http", "breaker", "ratelimit":
+		return "", false
+	}
+	return parts[1], true
+}
 
-	return &Cache{client: client, ttl: ttl}, nil
+// Entry is one cached search.
+type Entry struct {
+	Scraper    string      `json:"scraper"`
+	Query      string      `json:"query"`
+	Location   string      `json:"location,omitempty"`
+	Jobs       []model.Job `json:"jobs,omitempty"`
+	Err        string      `json:"err,omitempty"` // busca que falhou
+	FetchedAt  time.Time   `json:"fetched_at"`
+	FreshUntil time.Time   `json:"fresh_until"`
 }
 
-// Get retrieves cached jobs for the given scraper/query/location combination.
-// Returns the jobs and true if a valid cache entry exists, or nil and false otherwise.
-func (c *Cache) Get(ctx context.Context, scraper, query, location string) ([]model.Job, bool) {
-	key := buildKey(scraper, query, location)
+// Stale reports whether the entry is past its soft TTL.
+func (e Entry) Stale() bool {
+	return time.Now().After(e.FreshUntil)
+}
 
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		return nil, false
+// Negative reports whether the entry records an empty result or a failure.
+func (e Entry) Negative() bool {
+	return e.Err != "" || len(e.Jobs) == 0
+}
+
+// Age is how long ago the jobs were fetched from the source.
+func (e Entry) Age() time.Duration {
+	return time.Since(e.FetchedAt)
+}
+
+// Item is a stored entry, as returned by List.
+type Item struct {
+	Key     string
+	Entry   Entry
+	Expires time.Time // quando a entrada some do store
+	Size    int       // bytes
+	// Outdated marks entries of another model.SchemaVersion, which are
+	// never read; Entry may be incomplete.
+	Outdated bool
+}
+
+// Filter selects entries for List and Purge. Zero fields match everything.
+type Filter struct {
+	Scraper  string
+	Query    string
+	Outdated bool // apenas entradas de outra versão do schema
+}
+
+func (f Filter) match(it Item) bool {
+	switch {
+	case f.Outdated && !it.Outdated:
+		return false
+	case f.Scraper != "" && !strings.EqualFold(f.Scraper, it.Entry.Scraper):
+		return false
+	case f.Query != "" && !strings.EqualFold(f.Query, it.Entry.Query):
+		return false
 	}
+	return true
+}
+
+// New returns a cache over st (see store.Open for the backends). Closing
+// the cache closes st.
+func New(st store.Store, opts Options) Cache {
+	return &tieredCache{store: st, opts: opts.withDefaults()}
+}
+
+// tieredCache implements Cache over any store.Store.
+type tieredCache struct {
+	store store.Store
+	opts  Options
+}
 
-	var jobs []model.Job
-	if err := json.Unmarshal(data, &jobs); err != nil {
+func (c *tieredCache) Get(ctx context.Context, scraper, query, location string) ([]model.Job, bool) {
+	e, ok := c.Lookup(ctx, scraper, query, location)
+	if !ok || e.Stale() || e.Negative() {
 		return nil, false
 	}
+	return e.Jobs, true
+}
+
+func (c *tieredCache) Lookup(ctx context.Context, scraper, query, location string) (Entry, bool) {
+	data, ok, err := c.store.Get(ctx, buildKey(scraper, query, location, c.opts.Pages))
+	if err != nil || !ok {
+		return Entry{}, false
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+func (c *tieredCache) Set(ctx context.Context, scraper, query, location string, jobs []model.Job) error {
+	return c.put(ctx, Entry{Scraper: scraper, Query: query, Location: location, Jobs: jobs})
+}
+
+func (c *tieredCache) SetError(ctx context.Context, scraper, query, location string, err error) error {
+	return c.put(ctx, Entry{Scraper: scraper, Query: query, Location: location, Err: err.Error()})
+}
+
+func (c *tieredCache) List(ctx context.Context, f Filter) ([]Item, error) {
+	current := versionPrefix()
+	var items []Item
+	add := func(it Item, value []byte) {
+		if err := json.Unmarshal(value, &it.Entry); err != nil && !it.Outdated {
+			return // entrada corrompida: Lookup também a ignora
+		}
+		if f.match(it) {
+			items = append(items, it)
+		}
+	}
 
-	return jobs, true
+	err := c.store.Scan(ctx, keyPrefix+"*", func(key string, value []byte, expires time.Time) error {
+		add(Item{Key: key, Expires: expires, Size: len(value), Outdated: !strings.HasPrefix(key, current)}, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Scan(ctx, legacyPattern, func(key string, value []byte, expires time.Time) error {
+		if scraper, ok := legacyScraper(key); ok {
+			it := Item{Key: key, Expires: expires, Size: len(value), Outdated: true}
+			it.Entry.Scraper = scraper
+			add(it, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
+
+func (c *tieredCache) Purge(ctx context.Context, f Filter) (int, error) {
+	items, err := c.List(ctx, f)
+	if err != nil || len(items) == 0 {
+		return 0, err
+	}
+	keys := make([]string, len(items))
+	for i, it := range items {
+		keys[i] = it.Key
+	}
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
 }
 
-// Set stores jobs in the cache with the configured TTL.
-func (c *Cache) Set(ctx context.Context, scraper, query, location string, jobs []model.Job) error {
-	key := buildKey(scraper, query, location)
+// put stores e with its lifetimes: negative entries live NegativeTTL and
+// are never served stale; the rest are fresh for TTL and kept StaleTTL more.
+func (c *tieredCache) put(ctx context.Context, e Entry) error {
+	fresh, hard := c.opts.TTL, c.opts.TTL+c.opts.StaleTTL
+	if e.Negative() {
+		fresh, hard = c.opts.NegativeTTL, c.opts.NegativeTTL
+	}
+	e.FetchedAt = time.Now()
+	e.FreshUntil = e.FetchedAt.Add(fresh)
 
-	data, err := json.Marshal(jobs)
+	data, err := json.Marshal(e)
 	if err != nil {
 		return fmt.Errorf("cache: marshal error: %w", err)
 	}
+	return c.store.Set(ctx, buildKey(e.Scraper, e.Query, e.Location, c.opts.Pages), data, hard)
+}
 
-	return c.client.Set(ctx, key, data, c.ttl).Err()
+func (c *tieredCache) Close() error {
+	return c.store.Close()
 }
 
-// Close closes the Redis connection.
-func (c *Cache) Close() error {
-	return c.client.Close()
+func versionPrefix() string {
+	return fmt.Sprintf("%sv%d:", keyPrefix, model.SchemaVersion)
 }
 
-func buildKey(scraper, query, location string) string {
-	raw := strings.ToLower(scraper + ":" + query + ":" + location)
-	hash := sha256.Sum256([]byte(raw))
-	return fmt.Sprintf("gowork:%s:%x", strings.ToLower(scraper), hash[:8])
+// buildKey returns gowork:jobs:v<schema>:<scraper>:<sha256>. The scraper
+// stays readable so a store can be browsed by source. The page limit only
+// enters the hash above one page, so single-page entries keep their keys.
+func buildKey(scraper, query, location string, pages int) string {
+	raw := strings.ToLower(scraper + "\x00" + query + "\x00" + location)
+	if pages > 1 {
+		raw += "\x00" + strconv.Itoa(pages)
+	}
+	return fmt.Sprintf("%s%s:%x", versionPrefix(), strings.ToLower(scraper), sha256.Sum256([]byte(raw)))
 }
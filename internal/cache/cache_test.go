@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/store"
+)
+
+func TestLookup(t *testing.T) {
+	ctx := context.Background()
+	c := New(store.NewMemory(store.DefaultMemoryBytes, 10), Options{})
+	jobs := []model.Job{{Title: "Go Dev", URL: "https://a/1"}}
+
+	if _, ok := c.Lookup(ctx, "gupy", "golang", ""); ok {
+		t.Fatal("Lookup on empty cache found an entry")
+	}
+	if err := c.Set(ctx, "gupy", "golang", "", jobs); err != nil {
+		t.Fatal(err)
+	}
+	// A chave ignora maiúsculas.
+	got, ok := c.Get(ctx, "Gupy", "GoLang", "")
+	if !ok || len(got) != 1 || got[0].Title != "Go Dev" {
+		t.Errorf("Get = %v, %v", got, ok)
+	}
+
+	if err := c.SetError(ctx, "gupy", "rust", "", os.ErrDeadlineExceeded); err != nil {
+		t.Fatal(err)
+	}
+	e, ok := c.Lookup(ctx, "gupy", "rust", "")
+	if !ok || !e.Negative() || e.Err == "" {
+		t.Errorf("Lookup of a failure = %+v, %v", e, ok)
+	}
+	if _, ok := c.Get(ctx, "gupy", "rust", ""); ok {
+		t.Error("Get returned a failure")
+	}
+}
+
+func TestLookupPages(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMemory(store.DefaultMemoryBytes, 10)
+	one, three := New(st, Options{}), New(st, Options{Pages: 3})
+
+	if err := one.Set(ctx, "gupy", "golang", "", []model.Job{{Title: "Página 1"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Uma página não serve uma busca de três.
+	if _, ok := three.Lookup(ctx, "gupy", "golang", ""); ok {
+		t.Fatal("Lookup with Pages 3 found the single-page entry")
+	}
+	if err := three.Set(ctx, "gupy", "golang", "", []model.Job{{Title: "Página 1"}, {Title: "Página 3"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := three.Get(ctx, "gupy", "golang", ""); !ok || len(got) != 2 {
+		t.Errorf("Get with Pages 3 = %v, %v, want its own entry", got, ok)
+	}
+	if got, ok := New(st, Options{Pages: 1}).Get(ctx, "gupy", "golang", ""); !ok || len(got) != 1 {
+		t.Errorf("Get with Pages 1 = %v, %v, want the single-page entry", got, ok)
+	}
+
+	// Entradas de uma página mantêm a chave de antes do limite.
+	legacy := fmt.Sprintf("%sgupy:%x", versionPrefix(), sha256.Sum256([]byte("gupy\x00golang\x00")))
+	if got := buildKey("gupy", "golang", "", 1); got != legacy {
+		t.Errorf("buildKey with one page = %q, want %q", got, legacy)
+	}
+}
+
+func TestListOutdated(t *testing.T) {
+	ctx := context.Background()
+
+	mem := store.NewMemory(store.DefaultMemoryBytes, 100)
+	dir := t.TempDir()
+	file, err := store.NewFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, st := range map[string]store.Store{"memory": mem, "file": file} {
+		t.Run(name, func(t *testing.T) {
+			c := New(st, Options{})
+			if err := c.Set(ctx, "gupy", "golang", "", []model.Job{{Title: "Go Dev"}}); err != nil {
+				t.Fatal(err)
+			}
+			// Versão anterior do schema, chave sem versão e chaves de outros namespaces.
+			st.Set(ctx, "gowork:jobs:v1:gupy:abc", []byte(`{"scraper":"gupy","query":"java"}`), time.Hour)
+			st.Set(ctx, "gowork:gupy:0123456789abcdef", []byte(`{"jobs":[]}`), time.Hour)
+			st.Set(ctx, "gowork:http:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", []byte(`{}`), time.Hour)
+			st.Set(ctx, "gowork:breaker:deadbeefcafebabe", []byte(`1`), time.Hour)
+
+			items, err := c.List(ctx, Filter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := keys(items), []string{
+				"gowork:gupy:0123456789abcdef",
+				"gowork:jobs:v1:gupy:abc",
+				buildKey("gupy", "golang", "", 1),
+			}; !slices.Equal(got, want) {
+				t.Errorf("List = %v, want %v", got, want)
+			}
+
+			outdated, err := c.List(ctx, Filter{Outdated: true, Scraper: "gupy"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := keys(outdated), []string{"gowork:gupy:0123456789abcdef", "gowork:jobs:v1:gupy:abc"}; !slices.Equal(got, want) {
+				t.Errorf("List(outdated) = %v, want %v", got, want)
+			}
+
+			n, err := c.Purge(ctx, Filter{Outdated: true})
+			if err != nil || n != 2 {
+				t.Errorf("Purge = %d, %v, want 2", n, err)
+			}
+			if items, _ := c.List(ctx, Filter{}); len(items) != 1 || items[0].Outdated {
+				t.Errorf("after purge List = %v, want only the current entry", keys(items))
+			}
+		})
+	}
+}
+
+// Arquivos gravados antes de o store guardar a chave no JSON.
+func TestListKeylessFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	expires := time.Now().Add(time.Hour).Format(time.RFC3339)
+	legacy := map[string]string{
+		"gowork_gupy_0123456789abcdef.json":   `{"expires":"` + expires + `","jobs":[{"Title":"Go Dev"}]}`,
+		"gowork_gupy_fedcba9876543210.json":   `{"expires":"` + expires + `","value":{"scraper":"gupy","query":"go"}}`,
+		"gowork_jobs_v1_gupy_aaaa.json":       `{"expires":"` + expires + `","value":{"scraper":"gupy"}}`,
+		"0123456789abcdef0123456789abcd.json": `{"expires":"` + expires + `","value":"eyJ9"}`,
+	}
+	for name, data := range legacy {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := store.NewFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New(st, Options{})
+
+	items, err := c.List(ctx, Filter{Outdated: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"gowork:gupy:0123456789abcdef", "gowork:gupy:fedcba9876543210", "gowork:jobs:v1:gupy:aaaa"}
+	if got := keys(items); !slices.Equal(got, want) {
+		t.Fatalf("List = %v, want %v", got, want)
+	}
+	for _, it := range items {
+		if it.Entry.Scraper != "gupy" {
+			t.Errorf("%s: scraper = %q, want gupy", it.Key, it.Entry.Scraper)
+		}
+	}
+
+	if n, err := c.Purge(ctx, Filter{Outdated: true}); err != nil || n != 3 {
+		t.Fatalf("Purge = %d, %v, want 3", n, err)
+	}
+	left, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(left) != 1 {
+		t.Errorf("files left after purge: %v, want only the unrelated one", left)
+	}
+}
+
+func keys(items []Item) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = it.Key
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/geo"
+	"github.com/rsilvagit/go-work/internal/scheduler"
+)
+
+// Config is the go-work configuration file, in JSON.
+//
+//	{
+//	  "profiles": [
+//	    {"name": "golang", "schedule": "0 12 * * 1-5", "jitter": "5m",
+//	     "query": "golang,go", "modelo": "remoto,hibrido"}
+//	  ],
+//	  "domains": {
+//	    "gupy.io": {"min_delay": "3s", "max_delay": "8s", "burst": 2}
+//	  }
+//	}
+type Config struct {
+	Profiles []Profile         `json:"profiles"`
+	Domains  map[string]Domain `json:"domains"`
+}
+
+// Profile is a named search run on its own schedule by "go-work serve".
+// Empty filter fields mean "no filter", like the CLI flags.
+type Profile struct {
+	Name     string   `json:"name"`
+	Schedule string   `json:"schedule"` // cron ou "@every 6h"
+	Jitter   Duration `json:"jitter"`
+	Query    string   `json:"query"`
+	Location string   `json:"location"`
+	Tipo     string   `json:"tipo"`
+	Modelo   string   `json:"modelo"`
+	Nivel    string   `json:"nivel"`
+	Regiao   string   `json:"regiao"`
+	UF       string   `json:"uf"`
+	Cidade   string   `json:"cidade"`
+}
+
+// Domain overrides the crawl policy of a domain and its subdomains. Empty
+// fields keep the values from the flags.
+type Domain struct {
+	MinDelay     Duration `json:"min_delay"`
+	MaxDelay     Duration `json:"max_delay"`
+	Burst        int      `json:"burst"`
+	IgnoreRobots bool     `json:"ignore_robots"`
+}
+
+// Load reads and validates the configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks every profile and domain, returning all problems found at once.
+func (c *Config) Validate() error {
+	var errs []error
+	names := make(map[string]bool)
+	for i, p := range c.Profiles {
+		label := p.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+			errs = append(errs, fmt.Errorf("config: profile %s: name is required", label))
+		} else if names[p.Name] {
+			errs = append(errs, fmt.Errorf("config: profile %s: duplicated name", label))
+		}
+		names[p.Name] = true
+
+		if strings.TrimSpace(p.Query) == "" {
+			errs = append(errs, fmt.Errorf("config: profile %s: query is required", label))
+		}
+		if _, err := scheduler.Parse(p.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("config: profile %s: %w", label, err))
+		}
+		if p.Jitter < 0 {
+			errs = append(errs, fmt.Errorf("config: profile %s: jitter must not be negative", label))
+		}
+		for _, uf := range strings.Split(p.UF, ",") {
+			if uf = strings.TrimSpace(uf); uf == "" {
+				continue
+			}
+			if _, ok := geo.State(uf); !ok {
+				errs = append(errs, fmt.Errorf("config: profile %s: unknown uf %q", label, uf))
+			}
+		}
+	}
+
+	for name, d := range c.Domains {
+		if name == "" || strings.ContainsAny(name, "/:") {
+			errs = append(errs, fmt.Errorf("config: domain %q: must be a bare domain like \"gupy.io\"", name))
+		}
+		if d.MinDelay < 0 || d.MaxDelay < 0 {
+			errs = append(errs, fmt.Errorf("config: domain %s: delays must not be negative", name))
+		}
+		if d.Burst < 0 {
+			errs = append(errs, fmt.Errorf("config: domain %s: burst must not be negative", name))
+		}
+		if d.MinDelay > 0 && d.MaxDelay > 0 && d.MinDelay > d.MaxDelay {
+			errs = append(errs, fmt.Errorf("config: domain %s: min_delay is greater than max_delay", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration that decodes from JSON strings like "5m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %w", err)
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
@@ -0,0 +1,122 @@
+package dispatch
+
+import (
+	"context"
+	"sort"
+)
+
+// Task is one unit of work, typically a scraper×query search.
+type Task struct {
+	// Source groups tasks that hit the same host; it is the unit of the
+	// per-source limit and of the fair interleaving.
+	Source string
+	// Priority orders tasks: lower runs first. Ties keep insertion order.
+	Priority int
+	Run      func(ctx context.Context)
+}
+
+// Options configures the concurrency limits.
+type Options struct {
+	Concurrency int // tasks running at once, across all sources (default 4)
+	PerSource   int // tasks running at once for the same source (default 2)
+	// Progress, when set, is called after each task finishes, from the
+	// dispatcher goroutine.
+	Progress func(t Task, done, total int)
+}
+
+func (o Options) withDefaults() Options {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.PerSource <= 0 {
+		o.PerSource = 2
+	}
+	return o
+}
+
+// Run executes every task, respecting the global and per-source limits,
+// and blocks until all of them have returned. The next task is always the
+// lowest Priority among the sources below their limit; on ties sources take
+// turns, so one source with many queries can't monopolize the workers.
+//
+// Once ctx is done no more tasks are started: Run waits for the running
+// ones and returns those still queued, which is empty otherwise.
+func Run(ctx context.Context, tasks []Task, opts Options) (skipped []Task) {
+	opts = opts.withDefaults()
+
+	var sources []string
+	queues := make(map[string][]Task)
+	for _, t := range tasks {
+		if _, ok := queues[t.Source]; !ok {
+			sources = append(sources, t.Source)
+		}
+		queues[t.Source] = append(queues[t.Source], t)
+	}
+	for _, src := range sources {
+		q := queues[src]
+		sort.SliceStable(q, func(i, j int) bool { return q[i].Priority < q[j].Priority })
+	}
+
+	var (
+		running = make(map[string]int)
+		active  int
+		done    int
+		turn    int // fonte que tem preferência no próximo empate
+		results = make(chan Task)
+	)
+
+	// pick removes and returns the next runnable task.
+	pick := func() (Task, bool) {
+		best := -1
+		for i := range sources {
+			idx := (turn + i) % len(sources)
+			src := sources[idx]
+			if len(queues[src]) == 0 || running[src] >= opts.PerSource {
+				continue
+			}
+			if best < 0 || queues[src][0].Priority < queues[sources[best]][0].Priority {
+				best = idx
+			}
+		}
+		if best < 0 {
+			return Task{}, false
+		}
+		src := sources[best]
+		t := queues[src][0]
+		queues[src] = queues[src][1:]
+		turn = (best + 1) % len(sources)
+		return t, true
+	}
+
+	for {
+		// Contexto encerrado: as tarefas na fila não começam.
+		for ctx.Err() == nil && active < opts.Concurrency {
+			t, ok := pick()
+			if !ok {
+				break
+			}
+			running[t.Source]++
+			active++
+			go func(t Task) {
+				t.Run(ctx)
+				results <- t
+			}(t)
+		}
+		if active == 0 {
+			break
+		}
+
+		t := <-results
+		running[t.Source]--
+		active--
+		done++
+		if opts.Progress != nil {
+			opts.Progress(t, done, len(tasks))
+		}
+	}
+
+	for _, src := range sources {
+		skipped = append(skipped, queues[src]...)
+	}
+	return skipped
+}
@@ -0,0 +1,204 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gauge tracks how many tasks run at once and the highest count seen.
+type gauge struct {
+	now, peak atomic.Int32
+}
+
+func (g *gauge) enter() {
+	n := g.now.Add(1)
+	for {
+		p := g.peak.Load()
+		if n <= p || g.peak.CompareAndSwap(p, n) {
+			return
+		}
+	}
+}
+
+func (g *gauge) leave() { g.now.Add(-1) }
+
+func TestRunLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		sources     int
+		perSource   int // tarefas por fonte
+		opts        Options
+		wantPeak    int32
+		perSourceAt int32 // pico máximo de cada fonte
+	}{
+		{"global cap", 6, 4, Options{Concurrency: 3, PerSource: 10}, 3, 3},
+		{"per-source cap", 2, 10, Options{Concurrency: 10, PerSource: 2}, 4, 2},
+		{"both", 4, 5, Options{Concurrency: 5, PerSource: 1}, 4, 1},
+		{"defaults", 3, 6, Options{}, 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				total   gauge
+				sources = make([]gauge, tt.sources)
+				ran     atomic.Int32
+				tasks   []Task
+			)
+			for s := range tt.sources {
+				for range tt.perSource {
+					tasks = append(tasks, Task{
+						Source: fmt.Sprintf("fonte%d", s),
+						Run: func(context.Context) {
+							total.enter()
+							sources[s].enter()
+							time.Sleep(5 * time.Millisecond)
+							sources[s].leave()
+							total.leave()
+							ran.Add(1)
+						},
+					})
+				}
+			}
+
+			if skipped := Run(context.Background(), tasks, tt.opts); len(skipped) != 0 {
+				t.Errorf("Run skipped %d tasks", len(skipped))
+			}
+			if n := ran.Load(); int(n) != len(tasks) {
+				t.Errorf("%d tasks ran, want %d", n, len(tasks))
+			}
+			if p := total.peak.Load(); p != tt.wantPeak {
+				t.Errorf("peak concurrency = %d, want %d", p, tt.wantPeak)
+			}
+			for i := range sources {
+				if p := sources[i].peak.Load(); p > tt.perSourceAt {
+					t.Errorf("fonte%d: peak concurrency = %d, want at most %d", i, p, tt.perSourceAt)
+				}
+			}
+		})
+	}
+}
+
+func TestRunOrder(t *testing.T) {
+	// Cada tarefa é "fonte prioridade nome"; com um worker a ordem é exata.
+	type spec struct {
+		source   string
+		priority int
+		name     string
+	}
+	tests := []struct {
+		name  string
+		tasks []spec
+		want  []string
+	}{
+		{
+			"priority first",
+			[]spec{{"a", 2, "a2"}, {"a", 0, "a0"}, {"a", 1, "a1"}},
+			[]string{"a0", "a1", "a2"},
+		},
+		{
+			"ties keep insertion order",
+			[]spec{{"a", 0, "x"}, {"a", 0, "y"}, {"a", 0, "z"}},
+			[]string{"x", "y", "z"},
+		},
+		{
+			"sources take turns",
+			[]spec{{"a", 0, "a1"}, {"a", 0, "a2"}, {"a", 0, "a3"}, {"b", 0, "b1"}, {"b", 0, "b2"}, {"c", 0, "c1"}},
+			[]string{"a1", "b1", "c1", "a2", "b2", "a3"},
+		},
+		{
+			"priority beats the turn",
+			[]spec{{"a", 0, "a0"}, {"a", 0, "a0'"}, {"a", 1, "a1"}, {"b", 1, "b1"}},
+			[]string{"a0", "a0'", "b1", "a1"},
+		},
+		{
+			"turns within a priority",
+			[]spec{{"a", 0, "a0"}, {"a", 1, "a1"}, {"a", 1, "a1'"}, {"b", 1, "b1"}, {"b", 1, "b1'"}},
+			[]string{"a0", "b1", "a1", "b1'", "a1'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				order []string
+				tasks []Task
+			)
+			for _, s := range tt.tasks {
+				tasks = append(tasks, Task{
+					Source:   s.source,
+					Priority: s.priority,
+					Run: func(context.Context) {
+						mu.Lock()
+						order = append(order, s.name)
+						mu.Unlock()
+					},
+				})
+			}
+			Run(context.Background(), tasks, Options{Concurrency: 1})
+			if !slices.Equal(order, tt.want) {
+				t.Errorf("order = %v, want %v", order, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	var (
+		done  []int
+		tasks = make([]Task, 5)
+	)
+	for i := range tasks {
+		tasks[i] = Task{Source: "a", Run: func(context.Context) {}}
+	}
+	Run(context.Background(), tasks, Options{Progress: func(_ Task, n, total int) {
+		if total != len(tasks) {
+			t.Errorf("total = %d, want %d", total, len(tasks))
+		}
+		done = append(done, n)
+	}})
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(done, want) {
+		t.Errorf("progress = %v, want %v", done, want)
+	}
+
+	if skipped := Run(context.Background(), nil, Options{}); skipped != nil {
+		t.Errorf("Run without tasks = %v", skipped)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran []string
+	task := func(name string, fn func()) Task {
+		return Task{Source: "a", Run: func(ctx context.Context) {
+			ran = append(ran, name)
+			if fn != nil {
+				fn()
+			}
+		}}
+	}
+	tasks := []Task{
+		task("first", nil),
+		task("cancels", cancel),
+		task("queued1", nil),
+		task("queued2", nil),
+	}
+
+	skipped := Run(ctx, tasks, Options{Concurrency: 1})
+	if want := []string{"first", "cancels"}; !slices.Equal(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped %d tasks, want the 2 queued ones", len(skipped))
+	}
+
+	// Contexto já encerrado: nada começa.
+	ran = nil
+	if skipped := Run(ctx, tasks, Options{}); len(skipped) != len(tasks) || len(ran) != 0 {
+		t.Errorf("with a canceled ctx ran %v and skipped %d, want nothing run", ran, len(skipped))
+	}
+}
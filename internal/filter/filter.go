@@ -1,9 +1,11 @@
 package filter
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/rsilvagit/go-work/internal/geo"
 	"github.com/rsilvagit/go-work/internal/model"
 )
 
@@ -16,45 +18,222 @@ type Options struct {
 	WorkModel string        // remoto, hibrido, presencial
 	Level     string        // junior, pleno, senior
 	Region    string        // text to match against Location
+	State     string        // UFs, by abbreviation or name (ex: "SP,RJ")
+	City      string        // municipalities (ex: "Campinas")
 	MaxAge    time.Duration // maximum age of job posting (default: 24h)
 }
 
+// Rule identifies a filter criterion.
+type Rule string
+
+const (
+	RuleMaxAge    Rule = "max_age"
+	RuleJobType   Rule = "tipo"
+	RuleWorkModel Rule = "modelo"
+	RuleLevel     Rule = "nivel"
+	RuleRegion    Rule = "regiao"
+	RuleState     Rule = "uf"
+	RuleCity      Rule = "cidade"
+)
+
+// Rules lists every rule in the order they are evaluated.
+var Rules = []Rule{RuleMaxAge, RuleJobType, RuleWorkModel, RuleLevel, RuleRegion, RuleState, RuleCity}
+
+// Stats counts, per rule, how many jobs were rejected by it. A job is
+// counted only for the first rule that rejected it.
+type Stats map[Rule]int
+
+// Dropped returns the total number of rejected jobs.
+func (s Stats) Dropped() int {
+	n := 0
+	for _, c := range s {
+		n += c
+	}
+	return n
+}
+
+// ValidationError is returned by Validate for a criterion that can never
+// match. Rule names the criterion, as in the CLI flags and API parameters.
+type ValidationError struct {
+	Rule  Rule
+	Value string
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("filter: %s %q: %s", e.Rule, e.Value, e.Msg)
+}
+
+// Validate reports criteria that can never match, like an unknown UF, as a
+// *ValidationError.
+func (o Options) Validate() error {
+	for _, term := range strings.Split(o.State, ",") {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		if _, ok := geo.State(term); !ok {
+			return &ValidationError{Rule: RuleState, Value: term, Msg: "unknown UF"}
+		}
+	}
+	return nil
+}
+
+// Rejection tells why a job was dropped: the first rule that rejected it,
+// what the rule required and the text it checked.
+type Rejection struct {
+	Job       model.Job
+	Rule      Rule
+	Criterion string // ex: "remoto, hibrido" ou "até 24h0m0s"
+	Checked   string // campo verificado pela regra, ex: `local: "Curitiba, PR"`
+}
+
 // Apply filters a slice of jobs, returning only those that match all criteria.
 func Apply(jobs []model.Job, opts Options) []model.Job {
+	result, _ := ApplyWithStats(jobs, opts)
+	return result
+}
+
+// ApplyWithStats is like Apply but also reports how many jobs each rule rejected.
+func ApplyWithStats(jobs []model.Job, opts Options) ([]model.Job, Stats) {
+	result, stats, _ := apply(jobs, opts, false)
+	return result, stats
+}
+
+// ApplyExplain is like ApplyWithStats but also returns, for each dropped
+// job, why it was dropped.
+func ApplyExplain(jobs []model.Job, opts Options) ([]model.Job, Stats, []Rejection) {
+	return apply(jobs, opts, true)
+}
+
+func apply(jobs []model.Job, opts Options, explain bool) ([]model.Job, Stats, []Rejection) {
 	if opts.MaxAge == 0 {
 		opts.MaxAge = DefaultMaxAge
 	}
 
-	var result []model.Job
+	var (
+		result   []model.Job
+		rejected []Rejection
+	)
+	stats := make(Stats)
 	for _, j := range jobs {
-		if matchJob(j, opts) {
+		r, dropped := check(j, opts)
+		if !dropped {
 			result = append(result, j)
+			continue
+		}
+		stats[r.Rule]++
+		if explain {
+			rejected = append(rejected, r)
 		}
 	}
-	return result
+	return result, stats, rejected
+}
+
+// Match reports whether a single job passes every criterion. It is used by
+// the streaming pipeline, which filters jobs as they arrive.
+func Match(j model.Job, opts Options) bool {
+	_, dropped := Explain(j, opts)
+	return !dropped
 }
 
-func matchJob(j model.Job, opts Options) bool {
+// Explain reports whether a job is dropped by the filters and, if so, why.
+func Explain(j model.Job, opts Options) (Rejection, bool) {
+	if opts.MaxAge == 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	return check(j, opts)
+}
+
+// check returns the first rule that rejects j, if any.
+func check(j model.Job, opts Options) (Rejection, bool) {
 	// Filtrar vagas mais antigas que MaxAge.
 	if !j.PostedAt.IsZero() && time.Since(j.PostedAt) > opts.MaxAge {
-		return false
+		return Rejection{
+			Job:       j,
+			Rule:      RuleMaxAge,
+			Criterion: "até " + opts.MaxAge.String(),
+			Checked: fmt.Sprintf("publicada em %s (há %s)",
+				j.PostedAt.Format("2006-01-02 15:04"), time.Since(j.PostedAt).Round(time.Minute)),
+		}, true
 	}
 
 	text := j.FullText()
+	for _, c := range []struct {
+		rule  Rule
+		terms string
+		field string
+		value string
+	}{
+		{RuleJobType, opts.JobType, "tipo", j.JobType},
+		{RuleWorkModel, opts.WorkModel, "modelo", j.WorkModel},
+		{RuleLevel, opts.Level, "nível", j.Level},
+		{RuleRegion, opts.Region, "local", j.Location},
+	} {
+		if c.terms != "" && !containsAny(text, c.terms) {
+			return Rejection{Job: j, Rule: c.rule, Criterion: c.terms, Checked: searched(c.field, c.value)}, true
+		}
+	}
 
-	if opts.JobType != "" && !containsAny(text, opts.JobType) {
-		return false
+	// UF e cidade comparam a localização normalizada, não o texto.
+	if opts.State != "" && !matchState(j.State, opts.State) {
+		return Rejection{Job: j, Rule: RuleState, Criterion: opts.State, Checked: place(j)}, true
 	}
-	if opts.WorkModel != "" && !containsAny(text, opts.WorkModel) {
-		return false
+	if opts.City != "" && !matchCity(j.City, opts.City) {
+		return Rejection{Job: j, Rule: RuleCity, Criterion: opts.City, Checked: place(j)}, true
 	}
-	if opts.Level != "" && !containsAny(text, opts.Level) {
+	return Rejection{}, false
+}
+
+// matchState checks if state is one of the comma-separated UFs, given by
+// abbreviation or name.
+func matchState(state, terms string) bool {
+	if state == "" {
 		return false
 	}
-	if opts.Region != "" && !containsAny(text, opts.Region) {
+	for _, term := range strings.Split(terms, ",") {
+		if uf, ok := geo.State(term); ok && uf.Sigla == state {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCity checks if city is one of the comma-separated municipalities,
+// ignoring case and accents.
+func matchCity(city, terms string) bool {
+	if city == "" {
 		return false
 	}
-	return true
+	for _, term := range strings.Split(terms, ",") {
+		if geo.Fold(term) == geo.Fold(city) {
+			return true
+		}
+	}
+	return false
+}
+
+// searched describes, for Rejection.Checked, the field a text rule is about.
+// The terms are looked up in every text field (see model.Job.FullText), but
+// the description would hide the field that matters in the explanation.
+func searched(field, value string) string {
+	if value == "" {
+		return field + " vazio (procurado também no título e na descrição)"
+	}
+	return fmt.Sprintf("%s: %q (procurado também no título e na descrição)", field, value)
+}
+
+// place describes the normalized location of a job, for Rejection.Checked.
+func place(j model.Job) string {
+	resolved := "localização não identificada"
+	switch {
+	case j.City != "" && j.State != "":
+		resolved = j.City + "/" + j.State
+	case j.City != "":
+		resolved = j.City
+	case j.State != "":
+		resolved = j.State
+	}
+	return fmt.Sprintf("%s (local: %s)", resolved, j.Location)
 }
 
 // containsAny checks if text contains any of the comma-separated terms.
@@ -69,5 +248,6 @@ func containsAny(text, terms string) bool {
 }
 
 func (o Options) isEmpty() bool {
-	return o.JobType == "" && o.WorkModel == "" && o.Level == "" && o.Region == "" && o.MaxAge == 0
+	return o.JobType == "" && o.WorkModel == "" && o.Level == "" && o.Region == "" &&
+		o.State == "" && o.City == "" && o.MaxAge == 0
 }
@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+func TestExplain(t *testing.T) {
+	job := model.Job{
+		Title:       "Desenvolvedor Go Pleno",
+		Description: strings.Repeat("Buscamos pessoa desenvolvedora para atuar em Curitiba e região. ", 5),
+		Location:    "São Paulo, São Paulo, Brasil",
+		WorkModel:   "hibrido",
+		City:        "São Paulo",
+		State:       "SP",
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		job     model.Job
+		rule    Rule
+		checked string // vazio: a vaga passa
+	}{
+		{name: "no filter", opts: Options{}, job: job},
+		{name: "region in location", opts: Options{Region: "são paulo"}, job: job},
+		{
+			name:    "region shows the location",
+			opts:    Options{Region: "Rio de Janeiro"},
+			job:     job,
+			rule:    RuleRegion,
+			checked: `local: "São Paulo, São Paulo, Brasil" (procurado também no título e na descrição)`,
+		},
+		{
+			// O termo aparece na descrição: a vaga passa.
+			name: "region in description",
+			opts: Options{Region: "curitiba"},
+			job:  job,
+		},
+		{
+			name:    "work model",
+			opts:    Options{WorkModel: "remoto"},
+			job:     job,
+			rule:    RuleWorkModel,
+			checked: `modelo: "hibrido" (procurado também no título e na descrição)`,
+		},
+		{
+			name:    "empty field",
+			opts:    Options{JobType: "estagio"},
+			job:     job,
+			rule:    RuleJobType,
+			checked: "tipo vazio (procurado também no título e na descrição)",
+		},
+		{
+			name:    "state",
+			opts:    Options{State: "RJ,MG"},
+			job:     job,
+			rule:    RuleState,
+			checked: "São Paulo/SP (local: São Paulo, São Paulo, Brasil)",
+		},
+		{name: "state by name", opts: Options{State: "são paulo"}, job: job},
+		{name: "city ignores accents", opts: Options{City: "sao paulo"}, job: job},
+		{
+			name:    "max age",
+			opts:    Options{MaxAge: time.Hour},
+			job:     model.Job{Title: "Go Dev", PostedAt: time.Now().Add(-2 * time.Hour)},
+			rule:    RuleMaxAge,
+			checked: "publicada em",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, dropped := Explain(tt.job, tt.opts)
+			if dropped != (tt.rule != "") {
+				t.Fatalf("Explain dropped = %v (%+v), want rule %q", dropped, r, tt.rule)
+			}
+			if !dropped {
+				return
+			}
+			if r.Rule != tt.rule {
+				t.Errorf("Rule = %q, want %q", r.Rule, tt.rule)
+			}
+			if !strings.HasPrefix(r.Checked, tt.checked) {
+				t.Errorf("Checked = %q, want %q", r.Checked, tt.checked)
+			}
+		})
+	}
+}
+
+func TestApplyWithStats(t *testing.T) {
+	jobs := []model.Job{
+		{Title: "Go Dev", WorkModel: "remoto", Location: "Brasil"},
+		{Title: "Go Dev", WorkModel: "presencial", Location: "Curitiba, Paraná, Brasil"},
+		{Title: "Go Dev", WorkModel: "hibrido", Location: "Brasil"},
+		{Title: "Go Dev", PostedAt: time.Now().Add(-48 * time.Hour)},
+	}
+	got, stats := ApplyWithStats(jobs, Options{WorkModel: "remoto,hibrido"})
+	if len(got) != 2 {
+		t.Errorf("Apply kept %d jobs, want 2", len(got))
+	}
+	if stats[RuleWorkModel] != 1 || stats[RuleMaxAge] != 1 || stats.Dropped() != 2 {
+		t.Errorf("stats = %v, want modelo=1 max_age=1", stats)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		opts  Options
+		value string // termo inválido; vazio: válido
+	}{
+		{Options{}, ""},
+		{Options{State: "SP, rj,Minas Gerais"}, ""},
+		{Options{State: "SP,XX"}, "XX"},
+		{Options{WorkModel: "remoto", State: " , São Paolo"}, "São Paolo"},
+	}
+	for _, tt := range tests {
+		err := tt.opts.Validate()
+		if tt.value == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) = %v, want nil", tt.opts, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Rule != RuleState || verr.Value != tt.value {
+			t.Errorf("Validate(%+v) = %v, want a uf error for %q", tt.opts, err, tt.value)
+		}
+	}
+}
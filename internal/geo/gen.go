@@ -0,0 +1,59 @@
+//go:build ignore
+
+// gen downloads the IBGE municipality list and rewrites municipios.csv.
+// Run it with "go generate ./internal/geo".
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const source = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios?view=nivelado"
+
+type municipio struct {
+	Nome string `json:"municipio-nome"`
+	UF   string `json:"UF-sigla"`
+}
+
+func main() {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(source)
+	if err != nil {
+		log.Fatalf("gen: baixando municípios: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("gen: IBGE respondeu %d", resp.StatusCode)
+	}
+
+	var list []municipio
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		log.Fatalf("gen: decodificando municípios: %v", err)
+	}
+	if len(list) < 5000 {
+		log.Fatalf("gen: lista incompleta (%d municípios)", len(list))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].UF != list[j].UF {
+			return list[i].UF < list[j].UF
+		}
+		return list[i].Nome < list[j].Nome
+	})
+
+	var b strings.Builder
+	b.WriteString("uf;nome\n")
+	for _, m := range list {
+		fmt.Fprintf(&b, "%s;%s\n", m.UF, m.Nome)
+	}
+	if err := os.WriteFile("municipios.csv", []byte(b.String()), 0o644); err != nil {
+		log.Fatalf("gen: gravando municipios.csv: %v", err)
+	}
+	log.Printf("gen: %d municípios gravados", len(list))
+}
@@ -0,0 +1,278 @@
+// Package geo normalizes free-text job locations ("São Paulo, SP, Brasil",
+// "Sao Paulo, São Paulo, Brazil", "Remoto") into a canonical city, state
+// and country, using an embedded gazetteer of Brazilian municipalities.
+package geo
+
+//go:generate go run gen.go
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// Brazil is the canonical country name for Brazilian locations.
+const Brazil = "Brasil"
+
+// Place is a resolved location. Empty fields could not be resolved.
+type Place struct {
+	City    string // nome canônico do município, ex: "São Paulo"
+	State   string // sigla da UF, ex: "SP"
+	Country string // ex: "Brasil"
+	Remote  bool
+}
+
+// municipiosCSV lists the municipalities as "uf;nome" lines, with a header.
+// Regenerate it from the IBGE API with "go generate ./internal/geo"; while
+// it holds only part of the list, names without a UF resolve to capitals
+// only.
+//
+//go:embed municipios.csv
+var municipiosCSV string
+
+type city struct {
+	name string
+	uf   string
+}
+
+// completeSize is the least number of municipalities of a complete list;
+// Brazil has about 5,570. gen.go refuses to write fewer.
+const completeSize = 5000
+
+type gazetteer struct {
+	cities  map[string][]city // nome normalizado -> municípios com esse nome
+	bySigla map[string]UF
+	byName  map[string]UF // nome normalizado da UF
+	// complete reports whether the list has every municipality, so that a
+	// name found once is known to be unique.
+	complete bool
+}
+
+var load = sync.OnceValue(func() *gazetteer {
+	return parseGazetteer(municipiosCSV)
+})
+
+// parseGazetteer builds a gazetteer from "uf;nome" lines after a header.
+func parseGazetteer(csv string) *gazetteer {
+	g := &gazetteer{
+		cities:  make(map[string][]city),
+		bySigla: make(map[string]UF, len(UFs)),
+		byName:  make(map[string]UF, len(UFs)),
+	}
+	for _, uf := range UFs {
+		g.bySigla[uf.Sigla] = uf
+		g.byName[Fold(uf.Name)] = uf
+	}
+	n := 0
+	lines := strings.Split(csv, "\n")
+	for _, line := range lines[1:] {
+		uf, name, ok := strings.Cut(strings.TrimSpace(line), ";")
+		if !ok {
+			continue
+		}
+		key := Fold(name)
+		g.cities[key] = append(g.cities[key], city{name: name, uf: uf})
+		n++
+	}
+	g.complete = n >= completeSize
+	return g
+}
+
+// countries maps common spellings to the canonical country name.
+var countries = map[string]string{
+	"brasil":         Brazil,
+	"brazil":         Brazil,
+	"br":             Brazil,
+	"bra":            Brazil,
+	"portugal":       "Portugal",
+	"argentina":      "Argentina",
+	"chile":          "Chile",
+	"uruguai":        "Uruguai",
+	"uruguay":        "Uruguai",
+	"paraguai":       "Paraguai",
+	"paraguay":       "Paraguai",
+	"colombia":       "Colômbia",
+	"mexico":         "México",
+	"peru":           "Peru",
+	"estados unidos": "Estados Unidos",
+	"united states":  "Estados Unidos",
+	"usa":            "Estados Unidos",
+	"eua":            "Estados Unidos",
+	"canada":         "Canadá",
+	"reino unido":    "Reino Unido",
+	"united kingdom": "Reino Unido",
+	"uk":             "Reino Unido",
+	"irlanda":        "Irlanda",
+	"ireland":        "Irlanda",
+	"alemanha":       "Alemanha",
+	"germany":        "Alemanha",
+	"espanha":        "Espanha",
+	"spain":          "Espanha",
+	"franca":         "França",
+	"france":         "França",
+	"holanda":        "Holanda",
+	"netherlands":    "Holanda",
+	"paises baixos":  "Holanda",
+}
+
+// remoteWords mark a location as remote work.
+var remoteWords = []string{"remoto", "remota", "remote", "home office", "homeoffice", "anywhere", "teletrabalho"}
+
+// Resolve normalizes a free-text location. A known state abbreviation or
+// name fixes the state; a municipality is then looked up in it. Without a
+// state, a municipality name is accepted when it is a state capital, or
+// when it is unique and the gazetteer is complete. A part next to a known
+// state that is not in the gazetteer is still taken as the city.
+func Resolve(location string) Place {
+	return load().resolve(location)
+}
+
+func (g *gazetteer) resolve(location string) Place {
+	var (
+		p     Place
+		names []string // partes que podem ser cidade ou estado, na ordem
+	)
+	for _, part := range splitParts(location) {
+		f := Fold(part)
+		if f == "" {
+			continue
+		}
+		if isRemote(f) {
+			p.Remote = true
+			continue
+		}
+		if c, ok := countries[f]; ok {
+			if p.Country == "" {
+				p.Country = c
+			}
+			continue
+		}
+		if uf, ok := g.bySigla[strings.ToUpper(f)]; ok && len(f) == 2 {
+			if p.State == "" {
+				p.State = uf.Sigla
+			}
+			continue
+		}
+		names = append(names, part)
+	}
+
+	if p.Country != "" && p.Country != Brazil {
+		if len(names) > 0 {
+			p.City = names[0]
+		}
+		return p
+	}
+
+	// Sem sigla, a última parte com nome de UF é o estado; sozinha, ela
+	// ainda pode ser a cidade homônima ("São Paulo").
+	if p.State == "" {
+		for i := len(names) - 1; i >= 0; i-- {
+			if uf, ok := g.byName[Fold(names[i])]; ok {
+				p.State = uf.Sigla
+				if len(names) > 1 {
+					names = append(names[:i:i], names[i+1:]...)
+				}
+				break
+			}
+		}
+	}
+
+	for _, n := range names {
+		if c, ok := g.city(n, p.State); ok {
+			p.City, p.State = c.name, c.uf
+			break
+		}
+	}
+	if p.City == "" && p.State != "" && len(names) > 0 {
+		// Fora do gazetteer, a parte que acompanha a UF é a cidade.
+		if _, isUF := g.byName[Fold(names[0])]; !isUF {
+			p.City = strings.TrimSpace(names[0])
+		}
+	}
+
+	if p.City != "" || p.State != "" {
+		p.Country = Brazil
+	}
+	return p
+}
+
+// city looks up a municipality by name, in state when it is set.
+func (g *gazetteer) city(name, state string) (city, bool) {
+	candidates := g.cities[Fold(name)]
+	if state != "" {
+		for _, c := range candidates {
+			if c.uf == state {
+				return c, true
+			}
+		}
+		return city{}, false
+	}
+	// Um nome único só é conclusivo com a lista completa: numa lista
+	// parcial, o homônimo pode ser o que faltou.
+	if len(candidates) == 1 && g.complete {
+		return candidates[0], true
+	}
+	// Nome ambíguo, ou lista parcial: fica com a capital, se uma delas for.
+	for _, c := range candidates {
+		if g.bySigla[c.uf].Capital == c.name {
+			return c, true
+		}
+	}
+	return city{}, false
+}
+
+// State returns the UF for an abbreviation ("SP") or name ("São Paulo").
+func State(s string) (UF, bool) {
+	g := load()
+	if uf, ok := g.bySigla[strings.ToUpper(strings.TrimSpace(s))]; ok {
+		return uf, true
+	}
+	uf, ok := g.byName[Fold(s)]
+	return uf, ok
+}
+
+// City returns the canonical name of a municipality, in state when it is
+// set (see Resolve for ambiguous names).
+func City(name, state string) (string, bool) {
+	c, ok := load().city(name, state)
+	return c.name, ok
+}
+
+func isRemote(folded string) bool {
+	for _, w := range remoteWords {
+		if strings.Contains(folded, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitParts splits a location on commas, slashes, pipes, parentheses and
+// spaced dashes, keeping hyphenated names ("Ji-Paraná") whole.
+func splitParts(location string) []string {
+	location = strings.NewReplacer(" - ", ",", " – ", ",", " — ", ",").Replace(location)
+	return strings.FieldsFunc(location, func(r rune) bool {
+		switch r {
+		case ',', '/', '|', ';', '(', ')', '\n':
+			return true
+		}
+		return false
+	})
+}
+
+var foldReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"'", "", "’", "", "-", " ", ".", "",
+)
+
+// Fold lowercases s, strips accents, apostrophes and dots, turns hyphens
+// into spaces and collapses whitespace, so spellings like "Sao Paulo" and
+// "São Paulo" or "Ji Parana" and "Ji-Paraná" compare equal.
+func Fold(s string) string {
+	return strings.Join(strings.Fields(foldReplacer.Replace(strings.ToLower(s))), " ")
+}
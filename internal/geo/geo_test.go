@@ -0,0 +1,71 @@
+package geo
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		location string
+		want     Place
+	}{
+		{"São Paulo, SP, Brasil", Place{City: "São Paulo", State: "SP", Country: Brazil}},
+		{"Sao Paulo, São Paulo, Brazil", Place{City: "São Paulo", State: "SP", Country: Brazil}},
+		{"São Paulo", Place{City: "São Paulo", State: "SP", Country: Brazil}},
+		{"Campinas - SP", Place{City: "Campinas", State: "SP", Country: Brazil}},
+		{"Belo Horizonte / MG", Place{City: "Belo Horizonte", State: "MG", Country: Brazil}},
+		{"Ouro Preto, MG", Place{City: "Ouro Preto", State: "MG", Country: Brazil}},
+		{"Minas Gerais", Place{State: "MG", Country: Brazil}},
+		{"Remoto", Place{Remote: true}},
+		{"Remoto - Brasil", Place{Country: Brazil, Remote: true}},
+		{"Home Office (Curitiba, PR)", Place{City: "Curitiba", State: "PR", Country: Brazil, Remote: true}},
+		{"Lisboa, Portugal", Place{City: "Lisboa", Country: "Portugal"}},
+		{"Berlin, Germany", Place{City: "Berlin", Country: "Alemanha"}},
+		{"", Place{}},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.location); got != tt.want {
+			t.Errorf("Resolve(%q) = %+v, want %+v", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestResolveAmbiguous(t *testing.T) {
+	g := parseGazetteer("uf;nome\nPI;Bom Jesus\nRS;Bom Jesus\nPR;Palmas\nTO;Palmas\nSP;Campinas\n")
+
+	tests := []struct {
+		location string
+		complete bool
+		want     Place
+	}{
+		// Homônimos sem UF não são resolvidos...
+		{"Bom Jesus", true, Place{}},
+		{"Bom Jesus - RS", true, Place{City: "Bom Jesus", State: "RS", Country: Brazil}},
+		{"Bom Jesus, Piauí", true, Place{City: "Bom Jesus", State: "PI", Country: Brazil}},
+		// ...a não ser que um deles seja capital.
+		{"Palmas", true, Place{City: "Palmas", State: "TO", Country: Brazil}},
+		{"Palmas, PR", true, Place{City: "Palmas", State: "PR", Country: Brazil}},
+		// Nome único sem UF só vale com a lista completa.
+		{"Campinas", true, Place{City: "Campinas", State: "SP", Country: Brazil}},
+		{"Campinas", false, Place{}},
+		{"Campinas, SP", false, Place{City: "Campinas", State: "SP", Country: Brazil}},
+	}
+	for _, tt := range tests {
+		g.complete = tt.complete
+		if got := g.resolve(tt.location); got != tt.want {
+			t.Errorf("resolve(%q) with complete=%v = %+v, want %+v", tt.location, tt.complete, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"São Paulo", "sao paulo"},
+		{"  Ji-Paraná ", "ji parana"},
+		{"Santa Bárbara d'Oeste", "santa barbara doeste"},
+		{"ITAPECERICA DA SERRA", "itapecerica da serra"},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package geo
+
+// UF is a Brazilian state (unidade federativa).
+type UF struct {
+	Code    int    // código IBGE
+	Sigla   string // ex: "SP"
+	Name    string // ex: "São Paulo"
+	Region  string // Norte, Nordeste, Centro-Oeste, Sudeste ou Sul
+	Capital string
+}
+
+// UFs lists the 26 states and the Federal District, by IBGE code.
+var UFs = []UF{
+	{11, "RO", "Rondônia", "Norte", "Porto Velho"},
+	{12, "AC", "Acre", "Norte", "Rio Branco"},
+	{13, "AM", "Amazonas", "Norte", "Manaus"},
+	{14, "RR", "Roraima", "Norte", "Boa Vista"},
+	{15, "PA", "Pará", "Norte", "Belém"},
+	{16, "AP", "Amapá", "Norte", "Macapá"},
+	{17, "TO", "Tocantins", "Norte", "Palmas"},
+	{21, "MA", "Maranhão", "Nordeste", "São Luís"},
+	{22, "PI", "Piauí", "Nordeste", "Teresina"},
+	{23, "CE", "Ceará", "Nordeste", "Fortaleza"},
+	{24, "RN", "Rio Grande do Norte", "Nordeste", "Natal"},
+	{25, "PB", "Paraíba", "Nordeste", "João Pessoa"},
+	{26, "PE", "Pernambuco", "Nordeste", "Recife"},
+	{27, "AL", "Alagoas", "Nordeste", "Maceió"},
+	{28, "SE", "Sergipe", "Nordeste", "Aracaju"},
+	{29, "BA", "Bahia", "Nordeste", "Salvador"},
+	{31, "MG", "Minas Gerais", "Sudeste", "Belo Horizonte"},
+	{32, "ES", "Espírito Santo", "Sudeste", "Vitória"},
+	{33, "RJ", "Rio de Janeiro", "Sudeste", "Rio de Janeiro"},
+	{35, "SP", "São Paulo", "Sudeste", "São Paulo"},
+	{41, "PR", "Paraná", "Sul", "Curitiba"},
+	{42, "SC", "Santa Catarina", "Sul", "Florianópolis"},
+	{43, "RS", "Rio Grande do Sul", "Sul", "Porto Alegre"},
+	{50, "MS", "Mato Grosso do Sul", "Centro-Oeste", "Campo Grande"},
+	{51, "MT", "Mato Grosso", "Centro-Oeste", "Cuiabá"},
+	{52, "GO", "Goiás", "Centro-Oeste", "Goiânia"},
+	{53, "DF", "Distrito Federal", "Centro-Oeste", "Brasília"},
+}
@@ -0,0 +1,198 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+// RecordSearch upserts the jobs returned by one search and remembers that
+// each of them was listed for this source/query/location, so a later run of
+// the same search can tell which postings disappeared. key is the identity
+// of a job, the caller's dedup key (model.Job.Key when nil); Missing and
+// MarkClosed must be given the same one.
+func (s *Store) RecordSearch(ctx context.Context, key func(model.Job) string, source, query, location string, jobs []model.Job, at time.Time) error {
+	key = keyOrDefault(key)
+	if err := s.upsert(ctx, key, jobs, at); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("history: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO sightings (key, source, query, location, last_seen)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(key, source, query, location) DO UPDATE SET last_seen = excluded.last_seen`)
+	if err != nil {
+		return fmt.Errorf("history: prepare sighting: %w", err)
+	}
+	defer stmt.Close()
+
+	source, query, location = searchKey(source, query, location)
+	for _, j := range jobs {
+		if _, err := stmt.ExecContext(ctx, key(j), source, query, location, at.Unix()); err != nil {
+			return fmt.Errorf("history: sighting %q: %w", key(j), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("history: commit: %w", err)
+	}
+	return nil
+}
+
+// Missing returns the open jobs previously listed by this search that are
+// not among present, comparing them by key.
+func (s *Store) Missing(ctx context.Context, key func(model.Job) string, source, query, location string, present []model.Job) ([]Record, error) {
+	key = keyOrDefault(key)
+	source, query, location = searchKey(source, query, location)
+	rows, err := s.db.QueryContext(ctx, `
+SELECT j.key, `+prefixed("j.", columns)+`
+FROM jobs j JOIN sightings s ON s.key = j.key
+WHERE s.source = ? AND s.query = ? AND s.location = ? AND j.closed_at IS NULL`,
+		source, query, location)
+	if err != nil {
+		return nil, fmt.Errorf("history: query missing: %w", err)
+	}
+	defer rows.Close()
+
+	keep := make(map[string]bool, len(present))
+	for _, j := range present {
+		keep[key(j)] = true
+	}
+
+	var missing []Record
+	for rows.Next() {
+		// A chave gravada: o key pode usar campos que o histórico não guarda.
+		var stored string
+		r, err := scanRecord(rows, &stored)
+		if err != nil {
+			return nil, err
+		}
+		if !keep[stored] {
+			missing = append(missing, r)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("history: query missing: %w", err)
+	}
+	return missing, nil
+}
+
+// MarkClosed sets closed_at for the given jobs, found by key. Jobs already
+// closed keep their original closing time.
+func (s *Store) MarkClosed(ctx context.Context, key func(model.Job) string, jobs []model.Job, at time.Time) error {
+	key = keyOrDefault(key)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("history: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, j := range jobs {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE jobs SET closed_at = ? WHERE key = ? AND closed_at IS NULL", at.Unix(), key(j))
+		if err != nil {
+			return fmt.Errorf("history: closing %q: %w", key(j), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("history: commit: %w", err)
+	}
+	return nil
+}
+
+// Doer executes HTTP requests. Satisfied by *httpclient.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ConfirmClosed checks candidates that vanished from a search and returns the
+// ones that are really gone. A job with a URL is only considered closed when
+// the URL answers 404 or 410, since a posting can also drop out of the first
+// result page; a job without URL is closed by its absence alone. Jobs whose
+// check fails for any other reason, including ctx expiring, are kept open.
+// At most concurrency URLs are checked at once (1 if it is not positive);
+// the result keeps the order of candidates.
+func ConfirmClosed(ctx context.Context, client Doer, candidates []Record, concurrency int) []model.Job {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	gone := make([]bool, len(candidates))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, r := range candidates {
+		if r.URL == "" {
+			gone[i] = true
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			ok, err := URLGone(ctx, client, r.URL)
+			gone[i] = err == nil && ok
+		}()
+	}
+	wg.Wait()
+
+	var closed []model.Job
+	for i, r := range candidates {
+		if gone[i] {
+			closed = append(closed, r.Job)
+		}
+	}
+	return closed
+}
+
+// URLGone reports whether a posting URL now answers 404 Not Found or
+// 410 Gone.
+func URLGone(ctx context.Context, client Doer, url string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("history: building request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("history: checking %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone, nil
+}
+
+func keyOrDefault(key func(model.Job) string) func(model.Job) string {
+	if key == nil {
+		return model.Job.Key
+	}
+	return key
+}
+
+func searchKey(source, query, location string) (string, string, string) {
+	return strings.ToLower(source), strings.ToLower(strings.TrimSpace(query)), strings.ToLower(strings.TrimSpace(location))
+}
+
+// prefixed qualifies every column in a comma-separated list with p.
+func prefixed(p, cols string) string {
+	parts := strings.Split(cols, ",")
+	for i, c := range parts {
+		parts[i] = p + strings.TrimSpace(c)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+func TestConfirmClosed(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		switch r.URL.Path {
+		case "/404":
+			w.WriteHeader(http.StatusNotFound)
+		case "/410":
+			w.WriteHeader(http.StatusGone)
+		case "/500":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	rec := func(title, path string) Record {
+		j := model.Job{Title: title, Company: "ACME"}
+		if path != "" {
+			j.URL = srv.URL + path
+		}
+		return Record{Job: j}
+	}
+	candidates := []Record{
+		rec("a", "/404"),
+		rec("b", "/200"),
+		rec("c", ""),
+		rec("d", "/410"),
+		rec("e", "/500"),
+		rec("f", "/404"),
+	}
+
+	closed := ConfirmClosed(context.Background(), srv.Client(), candidates, 2)
+	var got []string
+	for _, j := range closed {
+		got = append(got, j.Title)
+	}
+	if want := []string{"a", "c", "d", "f"}; !slices.Equal(got, want) {
+		t.Errorf("closed = %v, want %v", got, want)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d checks at once, want at most 2", p)
+	}
+}
+
+func TestConfirmClosedExpired(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	candidates := []Record{
+		{Job: model.Job{Title: "a", URL: srv.URL + "/a"}},
+		{Job: model.Job{Title: "b"}},
+	}
+	closed := ConfirmClosed(ctx, srv.Client(), candidates, 4)
+	if len(closed) != 1 || closed[0].Title != "b" {
+		t.Errorf("closed = %v, want only the job without URL", closed)
+	}
+}
+
+func TestRecordSearchKey(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Chave que ignora os parâmetros de rastreamento da URL.
+	key := func(j model.Job) string {
+		u, _, _ := strings.Cut(j.URL, "?")
+		return strings.ToLower(u)
+	}
+	a := model.Job{Title: "Go Dev", Company: "ACME", URL: "https://site.test/vaga/1?utm=email"}
+	b := model.Job{Title: "Rust Dev", Company: "ACME", URL: "https://site.test/vaga/2"}
+	now := time.Now()
+	if err := s.RecordSearch(ctx, key, "gupy", "golang", "", []model.Job{a, b}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Na busca seguinte a vaga a volta com outra URL de rastreamento.
+	present := []model.Job{a}
+	present[0].URL = "https://site.test/vaga/1?utm=feed"
+	missing, err := s.Missing(ctx, key, "GUPY", " golang ", "", present)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordTitles(missing); !slices.Equal(got, []string{"Rust Dev"}) {
+		t.Errorf("Missing = %v, want [Rust Dev]", got)
+	}
+	// Com model.Job.Key a URL nova não casa com a gravada: a vaga parece ter sumido.
+	missing, _ = s.Missing(ctx, nil, "gupy", "golang", "", present)
+	if got := recordTitles(missing); !slices.Equal(got, []string{"Go Dev", "Rust Dev"}) {
+		t.Errorf("Missing with the default key = %v, want both", got)
+	}
+
+	if err := s.MarkClosed(ctx, key, []model.Job{b}, now); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := s.Find(ctx, Query{Closed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordTitles(closed); !slices.Equal(got, []string{"Rust Dev"}) {
+		t.Errorf("closed = %v, want [Rust Dev]", got)
+	}
+	if missing, _ := s.Missing(ctx, key, "gupy", "golang", "", present); len(missing) != 0 {
+		t.Errorf("Missing after closing = %v, want none", recordTitles(missing))
+	}
+}
+
+func recordTitles(rs []Record) []string {
+	var out []string
+	for _, r := range rs {
+		out = append(out, r.Title)
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,304 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	key         TEXT PRIMARY KEY,
+	title       TEXT NOT NULL,
+	company     TEXT NOT NULL,
+	location    TEXT NOT NULL,
+	url         TEXT NOT NULL,
+	description TEXT NOT NULL,
+	source      TEXT NOT NULL,
+	posted_at   INTEGER,
+	job_type    TEXT NOT NULL,
+	work_model  TEXT NOT NULL,
+	level       TEXT NOT NULL,
+	salary      TEXT NOT NULL,
+	first_seen  INTEGER NOT NULL,
+	last_seen   INTEGER NOT NULL,
+	closed_at   INTEGER
+);
+CREATE INDEX IF NOT EXISTS jobs_source_idx ON jobs(source);
+CREATE INDEX IF NOT EXISTS jobs_last_seen_idx ON jobs(last_seen);
+CREATE TABLE IF NOT EXISTS sightings (
+	key       TEXT NOT NULL,
+	source    TEXT NOT NULL,
+	query     TEXT NOT NULL,
+	location  TEXT NOT NULL,
+	last_seen INTEGER NOT NULL,
+	PRIMARY KEY (key, source, query, location)
+);
+CREATE INDEX IF NOT EXISTS sightings_search_idx ON sightings(source, query, location);
+`
+
+// Record is a job as stored in the history database.
+type Record struct {
+	model.Job
+	FirstSeen time.Time
+	LastSeen  time.Time
+	ClosedAt  time.Time // zero while the job is still open
+}
+
+// Open reports whether the job has not been marked as closed.
+func (r Record) Open() bool {
+	return r.ClosedAt.IsZero()
+}
+
+// Lifetime returns how long the job stayed open. Uses the publication date
+// when known, otherwise the first time it was seen.
+func (r Record) Lifetime() time.Duration {
+	start := r.FirstSeen
+	if !r.PostedAt.IsZero() && r.PostedAt.Before(start) {
+		start = r.PostedAt
+	}
+	end := r.ClosedAt
+	if end.IsZero() {
+		end = r.LastSeen
+	}
+	return end.Sub(start)
+}
+
+// Store persists every scraped job in an embedded SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path and applies the schema.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("history: opening database: %w", err)
+	}
+	// SQLite serializa escritas; uma conexão evita "database is locked".
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("history: applying schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Upsert records jobs as seen at the given time. New jobs get first_seen=at,
+// known jobs have their fields and last_seen refreshed, and a job that
+// reappears after being closed is reopened. Jobs are keyed by model.Job.Key.
+func (s *Store) Upsert(ctx context.Context, jobs []model.Job, at time.Time) error {
+	return s.upsert(ctx, model.Job.Key, jobs, at)
+}
+
+func (s *Store) upsert(ctx context.Context, key func(model.Job) string, jobs []model.Job, at time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("history: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO jobs (key, title, company, location, url, description, source,
+	posted_at, job_type, work_model, level, salary, first_seen, last_seen)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET
+	title = excluded.title,
+	company = excluded.company,
+	location = excluded.location,
+	url = excluded.url,
+	description = excluded.description,
+	source = excluded.source,
+	posted_at = excluded.posted_at,
+	job_type = excluded.job_type,
+	work_model = excluded.work_model,
+	level = excluded.level,
+	salary = excluded.salary,
+	last_seen = excluded.last_seen,
+	closed_at = NULL`)
+	if err != nil {
+		return fmt.Errorf("history: prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	ts := at.Unix()
+	for _, j := range jobs {
+		_, err := stmt.ExecContext(ctx,
+			key(j), j.Title, j.Company, j.Location, j.URL, j.Description, j.Source,
+			nullTime(j.PostedAt), j.JobType, j.WorkModel, j.Level, j.Salary, ts, ts)
+		if err != nil {
+			return fmt.Errorf("history: upsert %q: %w", key(j), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("history: commit: %w", err)
+	}
+	return nil
+}
+
+// Query holds the criteria for listing past jobs. Empty fields mean "no filter".
+type Query struct {
+	Text   string    // matched against title and company
+	Source string    // ex: "gupy"
+	Since  time.Time // only jobs seen at or after this time
+	Open   bool      // only jobs still open
+	Closed bool      // only jobs already closed
+	Limit  int       // 0 means no limit
+}
+
+// Find returns the jobs matching q, most recently seen first.
+func (s *Store) Find(ctx context.Context, q Query) ([]Record, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if q.Text != "" {
+		where = append(where, "(title LIKE ? OR company LIKE ?)")
+		like := "%" + q.Text + "%"
+		args = append(args, like, like)
+	}
+	if q.Source != "" {
+		where = append(where, "source = ?")
+		args = append(args, strings.ToLower(q.Source))
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "last_seen >= ?")
+		args = append(args, q.Since.Unix())
+	}
+	if q.Open {
+		where = append(where, "closed_at IS NULL")
+	}
+	if q.Closed {
+		where = append(where, "closed_at IS NOT NULL")
+	}
+
+	query := "SELECT " + columns + " FROM jobs"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY last_seen DESC, first_seen DESC"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("history: query: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		r, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("history: query: %w", err)
+	}
+	return records, nil
+}
+
+// SourceStats summarizes how long the closed postings of a source stayed open.
+type SourceStats struct {
+	Source string
+	Total  int
+	Open   int
+	Closed int
+	Mean   time.Duration
+	Median time.Duration
+	Max    time.Duration
+}
+
+// Stats returns per-source counts and open-duration statistics, computed
+// over the jobs that were already closed.
+func (s *Store) Stats(ctx context.Context) ([]SourceStats, error) {
+	records, err := s.Find(ctx, Query{})
+	if err != nil {
+		return nil, err
+	}
+
+	bySource := make(map[string]*SourceStats)
+	lifetimes := make(map[string][]time.Duration)
+	for _, r := range records {
+		st, ok := bySource[r.Source]
+		if !ok {
+			st = &SourceStats{Source: r.Source}
+			bySource[r.Source] = st
+		}
+		st.Total++
+		if r.Open() {
+			st.Open++
+			continue
+		}
+		st.Closed++
+		lifetimes[r.Source] = append(lifetimes[r.Source], r.Lifetime())
+	}
+
+	stats := make([]SourceStats, 0, len(bySource))
+	for src, st := range bySource {
+		ds := lifetimes[src]
+		if len(ds) > 0 {
+			sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+			var sum time.Duration
+			for _, d := range ds {
+				sum += d
+			}
+			st.Mean = sum / time.Duration(len(ds))
+			st.Median = ds[len(ds)/2]
+			st.Max = ds[len(ds)-1]
+		}
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Source < stats[j].Source })
+	return stats, nil
+}
+
+const columns = `title, company, location, url, description, source, posted_at,
+	job_type, work_model, level, salary, first_seen, last_seen, closed_at`
+
+// scanRecord scans a row of columns, after the leading columns in head.
+func scanRecord(rows *sql.Rows, head ...any) (Record, error) {
+	var (
+		r                   Record
+		posted, closed      sql.NullInt64
+		firstSeen, lastSeen int64
+	)
+	err := rows.Scan(append(head, &r.Title, &r.Company, &r.Location, &r.URL, &r.Description, &r.Source,
+		&posted, &r.JobType, &r.WorkModel, &r.Level, &r.Salary, &firstSeen, &lastSeen, &closed)...)
+	if err != nil {
+		return Record{}, fmt.Errorf("history: scanning row: %w", err)
+	}
+	if posted.Valid {
+		r.PostedAt = time.Unix(posted.Int64, 0)
+	}
+	if closed.Valid {
+		r.ClosedAt = time.Unix(closed.Int64, 0)
+	}
+	r.FirstSeen = time.Unix(firstSeen, 0)
+	r.LastSeen = time.Unix(lastSeen, 0)
+	return r, nil
+}
+
+func nullTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
@@ -0,0 +1,205 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/metrics"
+)
+
+// ErrCircuitOpen is matched (via errors.Is) by every *CircuitOpenError.
+var ErrCircuitOpen = errors.New("httpclient: circuit open")
+
+// CircuitOpenError is returned without touching the network when the
+// circuit of the request's host is open.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time // quando o host volta a receber uma request de teste
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("httpclient: circuit open for %s until %s", e.Host, e.Until.Format(time.TimeOnly))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerStore shares open circuits between processes, so a host that
+// failed in one run is not hammered again by the next one.
+type BreakerStore interface {
+	// OpenUntil returns when the host's circuit closes, or the zero time
+	// if it is not open.
+	OpenUntil(ctx context.Context, host string) (time.Time, error)
+	// Open records that the host's circuit is open until the given time.
+	Open(ctx context.Context, host string, until time.Time) error
+}
+
+type circuitState int
+
+const (
+	stateClosed circuitState = iota
+	stateHalfOpen
+	stateOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case stateHalfOpen:
+		return "half-open"
+	case stateOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// outcome classifies a finished request for the breaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeNeutral is a request that says nothing about the host's
+	// health, like one canceled by the caller's deadline.
+	outcomeNeutral
+)
+
+type circuit struct {
+	state    circuitState
+	failures int       // falhas seguidas no estado fechado
+	until    time.Time // fim do cool-down no estado aberto
+	probing  bool      // request de teste em andamento no estado meio-aberto
+}
+
+// breaker is a per-host circuit breaker. After threshold consecutive
+// failures a host's circuit opens and requests fail fast for cooldown; then
+// a single probe request is let through (half-open), which either closes the
+// circuit or opens it again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	store     BreakerStore
+	log       *slog.Logger
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+func newBreaker(threshold int, cooldown time.Duration, store BreakerStore, log *slog.Logger) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		store:     store,
+		log:       log,
+		hosts:     make(map[string]*circuit),
+	}
+}
+
+// allow returns a *CircuitOpenError if the host must not be contacted now.
+func (b *breaker) allow(ctx context.Context, host string) error {
+	b.mu.Lock()
+	c := b.circuit(host)
+	state := c.state
+	b.mu.Unlock()
+
+	// Um circuito fechado localmente pode ter sido aberto por outro processo.
+	if state == stateClosed && b.store != nil {
+		until, err := b.store.OpenUntil(ctx, host)
+		if err != nil {
+			b.log.Debug("httpclient: falha ao consultar circuit breaker compartilhado", "host", host, "err", err)
+		} else if time.Now().Before(until) {
+			b.mu.Lock()
+			c.until = until
+			b.transition(host, c, stateOpen)
+			b.mu.Unlock()
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch c.state {
+	case stateOpen:
+		if time.Now().Before(c.until) {
+			return &CircuitOpenError{Host: host, Until: c.until}
+		}
+		b.transition(host, c, stateHalfOpen)
+		fallthrough
+	case stateHalfOpen:
+		if c.probing {
+			return &CircuitOpenError{Host: host, Until: c.until}
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// record updates the host's circuit with the outcome of a request that
+// allow let through.
+func (b *breaker) record(ctx context.Context, host string, o outcome) {
+	b.mu.Lock()
+	c := b.circuit(host)
+	c.probing = false
+
+	switch o {
+	case outcomeNeutral:
+		b.mu.Unlock()
+		return
+	case outcomeSuccess:
+		c.failures = 0
+		b.transition(host, c, stateClosed)
+		b.mu.Unlock()
+		return
+	}
+
+	c.failures++
+	if c.state != stateHalfOpen && c.failures < b.threshold {
+		b.mu.Unlock()
+		return
+	}
+	c.failures = 0
+	c.until = time.Now().Add(b.cooldown)
+	b.transition(host, c, stateOpen)
+	until := c.until
+	b.mu.Unlock()
+
+	if b.store != nil {
+		if err := b.store.Open(ctx, host, until); err != nil {
+			b.log.Debug("httpclient: falha ao compartilhar circuit breaker", "host", host, "err", err)
+		}
+	}
+}
+
+// circuit returns the host's circuit, creating it closed. b.mu must be held.
+func (b *breaker) circuit(host string) *circuit {
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	return c
+}
+
+// transition changes the circuit state, logging and exporting the change.
+// b.mu must be held.
+func (b *breaker) transition(host string, c *circuit, to circuitState) {
+	if c.state == to {
+		return
+	}
+	from := c.state
+	c.state = to
+	metrics.CircuitState.WithLabelValues(host).Set(float64(to))
+
+	switch to {
+	case stateOpen:
+		b.log.Warn("httpclient: circuito aberto", "host", host, "from", from.String(), "until", c.until.Format(time.TimeOnly))
+	case stateHalfOpen:
+		b.log.Info("httpclient: circuito meio-aberto, testando host", "host", host)
+	case stateClosed:
+		b.log.Info("httpclient: circuito fechado", "host", host)
+	}
+}
@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBreakerStore shares open circuits through Redis. Each open host is a
+// key that expires when its cool-down ends.
+type RedisBreakerStore struct {
+	client *redis.Client
+}
+
+// NewRedisBreakerStore returns a breaker store over client, which the
+// caller closes.
+func NewRedisBreakerStore(client *redis.Client) *RedisBreakerStore {
+	return &RedisBreakerStore{client: client}
+}
+
+func (s *RedisBreakerStore) OpenUntil(ctx context.Context, host string) (time.Time, error) {
+	v, err := s.client.Get(ctx, breakerKey(host)).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("httpclient: invalid breaker entry for %s: %w", host, err)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func (s *RedisBreakerStore) Open(ctx context.Context, host string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, breakerKey(host), until.UnixMilli(), ttl).Err()
+}
+
+func breakerKey(host string) string {
+	return "gowork:breaker:" + host
+}
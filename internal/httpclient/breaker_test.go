@@ -0,0 +1,237 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+var discardLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestClassify(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	bg := context.Background()
+	status := func(code int) *http.Response { return &http.Response{StatusCode: code} }
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want outcome
+	}{
+		{"ok", bg, status(200), nil, outcomeSuccess},
+		{"not found", bg, status(404), nil, outcomeSuccess},
+		{"forbidden answer", bg, status(403), nil, outcomeSuccess},
+		{"too many requests", bg, status(429), nil, outcomeFailure},
+		{"server error", bg, status(503), nil, outcomeFailure},
+		{"network error", bg, nil, errors.New("connection reset"), outcomeFailure},
+		{"caller canceled", canceled, nil, context.Canceled, outcomeNeutral},
+		{"not recorded", bg, nil, fmt.Errorf("%w: GET x", ErrNotRecorded), outcomeNeutral},
+		{"proxy blocked", bg, nil, &RetriesExhaustedError{Attempts: 3, StatusCode: 403}, outcomeNeutral},
+		{"retries exhausted on 503", bg, nil, &RetriesExhaustedError{Attempts: 3, StatusCode: 503}, outcomeFailure},
+		{
+			"proxy unreachable", bg, nil,
+			fmt.Errorf("httpclient: request failed: %w", &net.OpError{Op: "proxyconnect", Net: "tcp", Err: errors.New("refused")}),
+			outcomeNeutral,
+		},
+		{"dial error", bg, nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")}, outcomeFailure},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.ctx, tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: classify = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	const cooldown = 30 * time.Millisecond
+
+	// Cada passo chama allow (esperando ou não ErrCircuitOpen), registra um
+	// resultado ou espera o cool-down.
+	type step struct {
+		do   string // allow, ok, fail, neutral, wait
+		open bool   // allow: espera ErrCircuitOpen
+	}
+	var (
+		allow   = step{do: "allow"}
+		blocked = step{do: "allow", open: true}
+		ok      = step{do: "ok"}
+		fail    = step{do: "fail"}
+		neutral = step{do: "neutral"}
+		wait    = step{do: "wait"}
+	)
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"opens after threshold failures", []step{
+			allow, fail, allow, fail, allow, fail, blocked,
+		}},
+		{"success resets the count", []step{
+			allow, fail, allow, fail, allow, ok, allow, fail, allow, fail, allow,
+		}},
+		{"neutral outcomes do not count", []step{
+			allow, fail, allow, fail, allow, neutral, allow, neutral, allow, neutral, allow, fail, blocked,
+		}},
+		{"fails fast during cool-down", []step{
+			fail, fail, fail, blocked, blocked, blocked,
+		}},
+		{"one probe when half-open", []step{
+			fail, fail, fail, wait, allow, blocked, blocked,
+		}},
+		{"probe success closes", []step{
+			fail, fail, fail, wait, allow, ok, allow, allow, fail, allow, fail, allow,
+		}},
+		{"probe failure reopens at once", []step{
+			fail, fail, fail, wait, allow, fail, blocked, wait, allow, ok, allow,
+		}},
+		{"neutral probe lets another probe", []step{
+			fail, fail, fail, wait, allow, neutral, allow, blocked,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(3, cooldown, nil, discardLog)
+			ctx := context.Background()
+			for i, s := range tt.steps {
+				switch s.do {
+				case "allow":
+					err := b.allow(ctx, "site.test")
+					if got := errors.Is(err, ErrCircuitOpen); got != s.open {
+						t.Fatalf("step %d: allow = %v, want open %v", i, err, s.open)
+					}
+					var open *CircuitOpenError
+					if s.open && (!errors.As(err, &open) || open.Host != "site.test" || open.Until.IsZero()) {
+						t.Errorf("step %d: allow = %#v, want a *CircuitOpenError for the host", i, err)
+					}
+				case "ok":
+					b.record(ctx, "site.test", outcomeSuccess)
+				case "fail":
+					b.record(ctx, "site.test", outcomeFailure)
+				case "neutral":
+					b.record(ctx, "site.test", outcomeNeutral)
+				case "wait":
+					time.Sleep(cooldown + 10*time.Millisecond)
+				}
+			}
+			// Os circuitos são por host.
+			if err := b.allow(ctx, "other.test"); err != nil {
+				t.Errorf("other host: allow = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// Um proxy que responde 403 a tudo esgota as tentativas, mas não abre o
+// circuito do site.
+func TestBreakerIgnoresProxyBlocks(t *testing.T) {
+	a, b := newFakeProxy(t), newFakeProxy(t)
+	a.status.Store(http.StatusForbidden)
+	b.status.Store(http.StatusForbidden)
+	c, err := New(Options{
+		Proxies:          []string{a.URL, b.URL},
+		ProxyCooldown:    time.Millisecond,
+		MinDelay:         time.Microsecond,
+		MaxDelay:         time.Microsecond,
+		MaxRetries:       2,
+		RetryBase:        time.Millisecond,
+		IgnoreRobots:     true,
+		BreakerThreshold: 1,
+		Logger:           discardLog,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 3 {
+		req, _ := http.NewRequest(http.MethodGet, "http://site.test/", nil)
+		_, err := c.Do(req)
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: circuit opened by proxy blocks: %v", i, err)
+		}
+		var exhausted *RetriesExhaustedError
+		if !errors.As(err, &exhausted) || exhausted.StatusCode != http.StatusForbidden {
+			t.Fatalf("request %d: err = %v, want retries exhausted on 403", i, err)
+		}
+	}
+}
+
+func newMiniredis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestRedisBreakerStore(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newMiniredis(t)
+	s := NewRedisBreakerStore(client)
+
+	if until, err := s.OpenUntil(ctx, "site.test"); err != nil || !until.IsZero() {
+		t.Fatalf("OpenUntil on empty store = %v, %v", until, err)
+	}
+
+	until := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	if err := s.Open(ctx, "site.test", until); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.OpenUntil(ctx, "site.test")
+	if err != nil || !got.Equal(until) {
+		t.Errorf("OpenUntil = %v, %v, want %v", got, err, until)
+	}
+	if ttl := mr.TTL("gowork:breaker:site.test"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL = %v, want the cool-down", ttl)
+	}
+
+	// A chave expira com o fim do cool-down.
+	mr.FastForward(time.Minute + time.Second)
+	if got, err := s.OpenUntil(ctx, "site.test"); err != nil || !got.IsZero() {
+		t.Errorf("OpenUntil after cool-down = %v, %v, want zero", got, err)
+	}
+
+	// Um cool-down já vencido não é gravado.
+	if err := s.Open(ctx, "old.test", time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("gowork:breaker:old.test") {
+		t.Error("expired circuit was stored")
+	}
+
+	mr.Set("gowork:breaker:bad.test", "não é número")
+	if _, err := s.OpenUntil(ctx, "bad.test"); err == nil {
+		t.Error("OpenUntil of a bad entry = nil error")
+	}
+}
+
+// O circuito aberto por um processo vale para os outros.
+func TestBreakerSharedStore(t *testing.T) {
+	ctx := context.Background()
+	_, client := newMiniredis(t)
+
+	first := newBreaker(1, time.Minute, NewRedisBreakerStore(client), discardLog)
+	second := newBreaker(1, time.Minute, NewRedisBreakerStore(client), discardLog)
+
+	if err := second.allow(ctx, "site.test"); err != nil {
+		t.Fatalf("allow before any failure = %v", err)
+	}
+	second.record(ctx, "site.test", outcomeNeutral)
+
+	first.record(ctx, "site.test", outcomeFailure)
+	if err := second.allow(ctx, "site.test"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow on the other process = %v, want ErrCircuitOpen", err)
+	}
+}
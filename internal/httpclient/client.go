@@ -3,12 +3,17 @@ package httpclient
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
+	"net"
 	"net/http"
-	"net/url"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/rsilvagit/go-work/internal/metrics"
 )
 
 var userAgents = []string{
@@ -29,10 +34,66 @@ var userAgents = []string{
 
 // Options configures the anti-ban HTTP client.
 type Options struct {
-	ProxyURL   string
+	ProxyURL   string // um proxy; somado a Proxies, se ambos forem informados
 	MinDelay   time.Duration
 	MaxDelay   time.Duration
-	MaxRetries int
+	MaxRetries int           // tentativas por request, incluindo a primeira (default 3)
+	RetryBase  time.Duration // base do backoff exponencial (default 1s)
+	RetryMax   time.Duration // teto do backoff e do Retry-After aceito (default 30s)
+	Logger     *slog.Logger  // default: slog.Default()
+
+	// Proxies is the proxy pool (http, https or socks5 URLs, with optional
+	// user:password). ProxyRotation is RotatePerRequest (default) or
+	// RotatePerHost; a proxy that fails or gets a 403/429 leaves the rotation
+	// for ProxyCooldown (default 5m).
+	Proxies       []string
+	ProxyRotation string
+	ProxyCooldown time.Duration
+
+	// Burst is how many requests to the same host may go out back to back
+	// before the MinDelay/MaxDelay spacing applies (default 1).
+	Burst int
+	// Limiter spaces the requests per host (default: a MemoryLimiter). If it
+	// fails, the client falls back to an in-memory limiter.
+	Limiter Limiter
+
+	// IgnoreRobots disables the robots.txt check for every host. When it is
+	// enabled, disallowed URLs fail with a *DisallowedError and a host's
+	// Crawl-delay raises its minimum delay.
+	IgnoreRobots bool
+	// Domains overrides the crawl policy per domain; a key also matches its
+	// subdomains.
+	Domains map[string]DomainPolicy
+
+	// RecordDir, when set, saves every request and response to that
+	// directory. ReplayDir serves them back instead of using the network,
+	// with no rate limiting or proxies. They are mutually exclusive.
+	RecordDir string
+	ReplayDir string
+
+	// HTTPCache, when set, caches GET responses as a private HTTP cache
+	// (RFC 9111): fresh responses are served without touching the site, and
+	// stale ones are revalidated with ETag/Last-Modified.
+	HTTPCache CacheStore
+
+	// BreakerThreshold is how many consecutive failed requests (transport
+	// errors, 429 or 5xx after retries) open a host's circuit (default 5;
+	// negative disables the circuit breaker).
+	BreakerThreshold int
+	// BreakerCooldown is how long an open circuit fails fast before a probe
+	// request is let through (default 1m).
+	BreakerCooldown time.Duration
+	// BreakerStore, when set, shares open circuits with other processes.
+	BreakerStore BreakerStore
+}
+
+// DomainPolicy overrides the crawl policy of one domain. Zero fields keep
+// the client-wide values.
+type DomainPolicy struct {
+	MinDelay     time.Duration
+	MaxDelay     time.Duration
+	Burst        int
+	IgnoreRobots bool
 }
 
 func (o Options) withDefaults() Options {
@@ -45,17 +106,48 @@ func (o Options) withDefaults() Options {
 	if o.MaxRetries == 0 {
 		o.MaxRetries = 3
 	}
+	if o.RetryBase == 0 {
+		o.RetryBase = time.Second
+	}
+	if o.RetryMax == 0 {
+		o.RetryMax = 30 * time.Second
+	}
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+	if o.Burst <= 0 {
+		o.Burst = 1
+	}
+	if o.ProxyCooldown == 0 {
+		o.ProxyCooldown = 5 * time.Minute
+	}
+	if o.BreakerThreshold == 0 {
+		o.BreakerThreshold = 5
+	}
+	if o.BreakerCooldown == 0 {
+		o.BreakerCooldown = time.Minute
+	}
 	return o
 }
 
 // Client wraps http.Client with anti-ban protections.
 type Client struct {
 	inner      *http.Client
-	mu         sync.Mutex
-	lastReq    map[string]time.Time
+	limiter    Limiter
+	fallback   *MemoryLimiter // usado se limiter falhar
+	burst      int
 	minDelay   time.Duration
 	maxDelay   time.Duration
 	maxRetries int
+	retryBase  time.Duration
+	retryMax   time.Duration
+	log        *slog.Logger
+	breaker    *breaker     // nil quando desativado
+	proxies    *proxyPool   // nil sem proxy
+	robots     *robotsCache // nil com IgnoreRobots
+	domains    map[string]DomainPolicy
+	replay     bool            // respostas gravadas: sem rate limit
+	cache      *cacheTransport // nil sem HTTPCache
 }
 
 // New creates a Client with the given options.
@@ -66,58 +158,235 @@ func New(opts Options) (*Client, error) {
 		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
 	}
 
+	var pool *proxyPool
+	proxies := opts.Proxies
 	if opts.ProxyURL != "" {
-		proxyURL, err := url.Parse(opts.ProxyURL)
+		proxies = append([]string{opts.ProxyURL}, proxies...)
+	}
+	if len(proxies) > 0 {
+		var err error
+		pool, err = newProxyPool(proxies, opts.ProxyRotation, opts.ProxyCooldown, opts.Logger)
 		if err != nil {
-			return nil, fmt.Errorf("httpclient: invalid proxy URL: %w", err)
+			return nil, err
 		}
-		transport.Proxy = http.ProxyURL(proxyURL)
+		transport.Proxy = proxyFunc
 	}
 
-	return &Client{
-		inner:      &http.Client{Transport: transport},
-		lastReq:    make(map[string]time.Time),
+	var rt http.RoundTripper = transport
+	switch {
+	case opts.RecordDir != "" && opts.ReplayDir != "":
+		return nil, fmt.Errorf("httpclient: record and replay are mutually exclusive")
+	case opts.ReplayDir != "":
+		rt, pool = &replayTransport{dir: opts.ReplayDir}, nil
+	case opts.RecordDir != "":
+		var err error
+		if rt, err = newRecordTransport(transport, opts.RecordDir); err != nil {
+			return nil, err
+		}
+	}
+
+	var cache *cacheTransport
+	if opts.HTTPCache != nil {
+		cache = &cacheTransport{next: rt, store: opts.HTTPCache, log: opts.Logger}
+		rt = cache
+	}
+
+	c := &Client{
+		cache:      cache,
+		proxies:    pool,
+		inner:      &http.Client{Transport: rt},
+		replay:     opts.ReplayDir != "",
+		limiter:    opts.Limiter,
+		fallback:   NewMemoryLimiter(),
+		burst:      opts.Burst,
 		minDelay:   opts.MinDelay,
 		maxDelay:   opts.MaxDelay,
 		maxRetries: opts.MaxRetries,
-	}, nil
+		retryBase:  opts.RetryBase,
+		retryMax:   opts.RetryMax,
+		log:        opts.Logger,
+		domains:    opts.Domains,
+	}
+	if c.limiter == nil {
+		c.limiter = c.fallback
+	}
+	if !opts.IgnoreRobots {
+		c.robots = newRobotsCache(c.do, opts.Logger)
+	}
+	if opts.BreakerThreshold > 0 {
+		c.breaker = newBreaker(opts.BreakerThreshold, opts.BreakerCooldown, opts.BreakerStore, opts.Logger)
+	}
+	return c, nil
 }
 
 // Do executes the request with UA rotation, realistic headers, rate limiting,
-// and retry with exponential backoff.
+// and retry with exponential backoff. If the host's circuit is open, Do fails
+// fast with a *CircuitOpenError; if robots.txt disallows the URL, with a
+// *DisallowedError.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	c.setHeaders(req)
+	ctx, host := req.Context(), req.URL.Host
+	if c.robots != nil && !c.policy(host).IgnoreRobots {
+		if err := c.robots.check(ctx, req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if c.breaker == nil {
+		return c.do(req)
+	}
 
-	if err := c.rateLimit(req.Context(), req.URL.Host); err != nil {
+	if err := c.breaker.allow(ctx, host); err != nil {
 		return nil, err
 	}
+	resp, err := c.do(req)
+	c.breaker.record(context.WithoutCancel(ctx), host, classify(ctx, resp, err))
+	return resp, err
+}
+
+// classify tells the breaker whether a request shows the host is unhealthy.
+func classify(ctx context.Context, resp *http.Response, err error) outcome {
+	switch {
+	case ctx.Err() != nil:
+		// Prazo ou cancelamento do chamador não diz nada sobre o host.
+		return outcomeNeutral
+	case errors.Is(err, ErrNotRecorded):
+		// No replay, falta de gravação é problema do teste, não do host.
+		return outcomeNeutral
+	case proxyFailure(err):
+		// Proxy bloqueado ou fora do ar: o host pode estar saudável.
+		return outcomeNeutral
+	case err != nil:
+		return outcomeFailure
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
 
-	var resp *http.Response
-	var err error
+// proxyFailure reports whether err is the proxy's fault rather than the
+// host's: a 403 that exhausted the retries over the proxy pool (only then is
+// a 403 retried), or a failure to connect to the proxy.
+func proxyFailure(err error) bool {
+	var exhausted *RetriesExhaustedError
+	if errors.As(err, &exhausted) && exhausted.StatusCode == http.StatusForbidden {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "proxyconnect"
+}
 
-	for attempt := range c.maxRetries {
-		resp, err = c.inner.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("httpclient: request failed: %w", err)
-		}
+// do sends the request, retrying idempotent requests on transient network
+// errors and on 429/5xx. The wait between attempts honors Retry-After and
+// otherwise uses full-jitter exponential backoff. When every attempt fails,
+// the error is a *RetriesExhaustedError and the response is nil.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	c.setHeaders(req)
 
-		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+	ctx, host := req.Context(), req.URL.Host
+	// Resposta fresca no cache não custa nada ao site: pula o rate limit.
+	if c.cache != nil {
+		if resp, ok := c.cache.fresh(req); ok {
 			return resp, nil
 		}
+	}
+	if !c.replay {
+		if err := c.rateLimit(ctx, host); err != nil {
+			return nil, err
+		}
+	}
+
+	attempts := c.maxRetries
+	if !idempotent(req) {
+		attempts = 1
+	}
 
-		resp.Body.Close()
-		backoff := time.Duration(1<<uint(attempt)) * 2 * time.Second
-		fmt.Printf("[httpclient] %s retornou %d, aguardando %v (tentativa %d/%d)\n",
-			req.URL.Host, resp.StatusCode, backoff, attempt+1, c.maxRetries)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("httpclient: rewinding request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		sent := req
+		var proxy *proxyEntry
+		if c.proxies != nil {
+			proxy = c.proxies.pick(host)
+			sent = withProxy(req, proxy)
+		}
+		resp, err := c.inner.Do(sent)
+		if proxy != nil {
+			c.proxyOutcome(ctx, proxy, host, resp, err)
+		}
+		last := attempt == attempts-1
+
+		var (
+			status = "error"
+			wait   time.Duration
+		)
+		if err != nil {
+			metrics.HTTPRequests.WithLabelValues(host, status).Inc()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !retryableErr(err) || attempts == 1 {
+				return nil, fmt.Errorf("httpclient: request failed: %w", err)
+			}
+			if last {
+				return nil, &RetriesExhaustedError{Attempts: attempts, Err: err}
+			}
+			wait = backoff(attempt, c.retryBase, c.retryMax)
+		} else {
+			status = strconv.Itoa(resp.StatusCode)
+			metrics.HTTPRequests.WithLabelValues(host, status).Inc()
+			// Com um pool, um 403 pode ser bloqueio do proxy: tenta outro.
+			retry := retryableStatus(resp.StatusCode) || (c.proxies != nil && resp.StatusCode == http.StatusForbidden)
+			if !retry || attempts == 1 {
+				return resp, nil
+			}
+
+			d, ok := retryAfter(resp)
+			discard(resp)
+			if last || (ok && d > c.retryMax) {
+				// Esperar mais que retryMax seria pior que desistir.
+				return nil, &RetriesExhaustedError{Attempts: attempt + 1, StatusCode: resp.StatusCode}
+			}
+			wait = backoff(attempt, c.retryBase, c.retryMax)
+			if ok {
+				wait = d
+			}
+		}
+
+		metrics.HTTPRetries.WithLabelValues(host, status).Inc()
+		attrs := []any{"host", host, "status", status,
+			"backoff", wait.Round(time.Millisecond), "attempt", attempt + 1, "max_attempts", attempts}
+		if err != nil {
+			attrs = append(attrs, "err", err)
+		}
+		c.log.Warn("httpclient: retry", attrs...)
 
 		select {
-		case <-time.After(backoff):
-		case <-req.Context().Done():
-			return nil, req.Context().Err()
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
+}
 
-	return resp, err
+// proxyOutcome logs which proxy served the request and takes it out of the
+// rotation if it failed or was blocked.
+func (c *Client) proxyOutcome(ctx context.Context, proxy *proxyEntry, host string, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		if ctx.Err() == nil {
+			c.proxies.fail(proxy, err.Error())
+		}
+		return
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		c.proxies.fail(proxy, "status "+strconv.Itoa(resp.StatusCode))
+	}
+	c.log.Debug("httpclient: request via proxy", "host", host, "proxy", proxy.url.Redacted(), "status", resp.StatusCode)
 }
 
 func (c *Client) setHeaders(req *http.Request) {
@@ -136,32 +405,76 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Connection", "keep-alive")
 }
 
-func (c *Client) rateLimit(ctx context.Context, host string) error {
-	c.mu.Lock()
-	last, ok := c.lastReq[host]
-	c.lastReq[host] = time.Now()
-	c.mu.Unlock()
+// policy returns the crawl policy of host: the entry of its domain or of the
+// closest parent domain in c.domains.
+func (c *Client) policy(host string) DomainPolicy {
+	if len(c.domains) == 0 {
+		return DomainPolicy{}
+	}
+	name := strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(name); err == nil {
+		name = h
+	}
+	for {
+		if p, ok := c.domains[name]; ok {
+			return p
+		}
+		_, parent, ok := strings.Cut(name, ".")
+		if !ok {
+			return DomainPolicy{}
+		}
+		name = parent
+	}
+}
+
+// delays returns the rate-limit window and burst of host: the client-wide
+// values, the domain overrides, and the robots.txt Crawl-delay as a floor.
+func (c *Client) delays(host string) (minDelay, maxDelay time.Duration, burst int) {
+	minDelay, maxDelay, burst = c.minDelay, c.maxDelay, c.burst
+	p := c.policy(host)
+	if p.Burst > 0 {
+		burst = p.Burst
+	}
+	if p.MinDelay > 0 {
+		minDelay = p.MinDelay
+	}
+	if p.MaxDelay > 0 {
+		maxDelay = p.MaxDelay
+	}
+	if c.robots != nil && !p.IgnoreRobots {
+		if d := c.robots.crawlDelay(host); d > minDelay {
+			minDelay = d
+		}
+	}
+	return minDelay, max(maxDelay, minDelay), burst
+}
 
-	if !ok {
-		return nil
+// rateLimit waits for the host's next slot. The spacing is drawn between
+// the host's minimum and maximum delay on every request.
+func (c *Client) rateLimit(ctx context.Context, host string) error {
+	minDelay, maxDelay, burst := c.delays(host)
+	interval := minDelay
+	if maxDelay > minDelay {
+		interval += time.Duration(rand.Int63n(int64(maxDelay - minDelay)))
 	}
 
-	elapsed := time.Since(last)
-	delay := c.minDelay + time.Duration(rand.Int63n(int64(c.maxDelay-c.minDelay)))
+	wait, err := c.limiter.Reserve(ctx, host, interval, burst)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.log.Warn("httpclient: rate limit compartilhado indisponível, usando o local", "host", host, "err", err)
+		wait, _ = c.fallback.Reserve(ctx, host, interval, burst)
+	}
 
-	if elapsed < delay {
-		wait := delay - elapsed
-		fmt.Printf("[httpclient] rate limit: aguardando %v antes de acessar %s\n", wait.Round(time.Millisecond), host)
+	if wait > 0 {
+		c.log.Info("httpclient: rate limit", "host", host, "wait", wait.Round(time.Millisecond))
 		select {
 		case <-time.After(wait):
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
-
-	c.mu.Lock()
-	c.lastReq[host] = time.Now()
-	c.mu.Unlock()
-
+	metrics.RateLimitWait.WithLabelValues(host).Observe(wait.Seconds())
 	return nil
 }
@@ -0,0 +1,297 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/metrics"
+)
+
+const (
+	// heuristicMax caps the freshness guessed from Last-Modified.
+	heuristicMax = 24 * time.Hour
+	// staleRetention is how long a stale entry with validators is kept,
+	// so it can still be revalidated with a cheap 304.
+	staleRetention = 7 * 24 * time.Hour
+)
+
+// clientVaried are request headers the client picks itself, so they say
+// nothing about which response the site meant: setHeaders draws a random
+// User-Agent per request and the transport adds Accept-Encoding (and
+// decompresses the body before it is stored). Varying on them would make
+// almost every lookup miss.
+var clientVaried = map[string]bool{
+	"User-Agent":      true,
+	"Accept-Encoding": true,
+}
+
+// CacheStore is the storage behind the HTTP response cache; every
+// store.Store satisfies it.
+type CacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// cachedResponse is a stored response, in a private cache (RFC 9111).
+type cachedResponse struct {
+	Status       int               `json:"status"`
+	Header       http.Header       `json:"header"`
+	Body         []byte            `json:"body"`
+	ResponseTime time.Time         `json:"response_time"`
+	Vary         map[string]string `json:"vary,omitempty"` // headers da request que selecionam a resposta
+}
+
+// age is the current age of the response: its Age header plus the time
+// it has been stored.
+func (cr *cachedResponse) age(now time.Time) time.Duration {
+	age := now.Sub(cr.ResponseTime)
+	if secs, err := strconv.Atoi(cr.Header.Get("Age")); err == nil && secs > 0 {
+		age += time.Duration(secs) * time.Second
+	}
+	return age
+}
+
+func (cr *cachedResponse) fresh(now time.Time) bool {
+	return cr.age(now) < freshnessLifetime(cr.Header, cr.ResponseTime)
+}
+
+func (cr *cachedResponse) hasValidators() bool {
+	return cr.Header.Get("ETag") != "" || cr.Header.Get("Last-Modified") != ""
+}
+
+// matches reports whether req selects this response, per its Vary header.
+// The headers in clientVaried are ignored.
+func (cr *cachedResponse) matches(req *http.Request) bool {
+	for name, v := range cr.Vary {
+		if !clientVaried[name] && req.Header.Get(name) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (cr *cachedResponse) response(req *http.Request) *http.Response {
+	header := cr.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(cr.age(time.Now()).Seconds())))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cr.Status, http.StatusText(cr.Status)),
+		StatusCode:    cr.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cr.Body)),
+		ContentLength: int64(len(cr.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControl parses a Cache-Control header into directive -> value.
+func cacheControl(h http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, field := range h.Values("Cache-Control") {
+		for _, part := range strings.Split(field, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if name != "" {
+				cc[strings.ToLower(name)] = strings.Trim(value, `"`)
+			}
+		}
+	}
+	return cc
+}
+
+// freshnessLifetime follows RFC 9111 for a private cache: max-age, then
+// Expires, then 10% of the time since Last-Modified (capped).
+func freshnessLifetime(h http.Header, responseTime time.Time) time.Duration {
+	cc := cacheControl(h)
+	if _, ok := cc["no-cache"]; ok {
+		return 0
+	}
+	if v, ok := cc["max-age"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		return 0
+	}
+
+	date := responseTime
+	if d, err := http.ParseTime(h.Get("Date")); err == nil {
+		date = d
+	}
+	if v := h.Get("Expires"); v != "" {
+		exp, err := http.ParseTime(v)
+		if err != nil {
+			return 0 // Expires inválido significa "já expirou"
+		}
+		return max(exp.Sub(date), 0)
+	}
+	if lm, err := http.ParseTime(h.Get("Last-Modified")); err == nil && date.After(lm) {
+		return min(date.Sub(lm)/10, heuristicMax)
+	}
+	return 0
+}
+
+// cacheTransport is a private HTTP cache: it serves fresh responses from
+// its store and revalidates stale ones with If-None-Match/If-Modified-Since.
+type cacheTransport struct {
+	next  http.RoundTripper
+	store CacheStore
+	log   *slog.Logger
+}
+
+// cacheKey namespaces the responses in a store shared with the job cache;
+// the URL is hashed to keep keys short and free of "/".
+func cacheKey(req *http.Request) string {
+	return fmt.Sprintf("gowork:http:%x", sha256.Sum256([]byte(req.Method+" "+req.URL.String())))
+}
+
+// cacheable reports whether the request may use the cache at all.
+func cacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	_, noStore := cacheControl(req.Header)["no-store"]
+	return !noStore
+}
+
+// lookup returns the stored response for req, if any.
+func (t *cacheTransport) lookup(req *http.Request) (*cachedResponse, bool) {
+	if !cacheable(req) {
+		return nil, false
+	}
+	data, ok, err := t.store.Get(req.Context(), cacheKey(req))
+	if err != nil {
+		t.log.Debug("httpclient: falha ao ler cache HTTP", "url", req.URL.Redacted(), "err", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	var cr cachedResponse
+	if err := json.Unmarshal(data, &cr); err != nil || !cr.matches(req) {
+		return nil, false
+	}
+	return &cr, true
+}
+
+// fresh returns a response served straight from the cache, if req has a
+// fresh one. The client calls it before rate limiting, since a hit costs
+// the site nothing.
+func (t *cacheTransport) fresh(req *http.Request) (*http.Response, bool) {
+	if _, noCache := cacheControl(req.Header)["no-cache"]; noCache {
+		return nil, false
+	}
+	cr, ok := t.lookup(req)
+	if !ok || !cr.fresh(time.Now()) {
+		return nil, false
+	}
+	metrics.HTTPCacheRequests.WithLabelValues(req.URL.Host, "hit").Inc()
+	return cr.response(req), true
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cr, ok := t.lookup(req)
+	if ok && cr.fresh(time.Now()) {
+		metrics.HTTPCacheRequests.WithLabelValues(req.URL.Host, "hit").Inc()
+		return cr.response(req), nil
+	}
+
+	// Resposta vencida com validadores: revalida em vez de baixar de novo.
+	sent := req
+	if ok && cr.hasValidators() {
+		sent = req.Clone(req.Context())
+		if etag := cr.Header.Get("ETag"); etag != "" {
+			sent.Header.Set("If-None-Match", etag)
+		}
+		if lm := cr.Header.Get("Last-Modified"); lm != "" {
+			sent.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		metrics.HTTPCacheRequests.WithLabelValues(req.URL.Host, "revalidated").Inc()
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified", "Vary"} {
+			if v := resp.Header.Values(name); len(v) > 0 {
+				cr.Header[name] = v
+			}
+		}
+		cr.Header.Del("Age")
+		cr.ResponseTime = time.Now()
+		t.save(req.Context(), req, cr)
+		return cr.response(req), nil
+	}
+
+	if !cacheable(req) {
+		return resp, nil
+	}
+	metrics.HTTPCacheRequests.WithLabelValues(req.URL.Host, "miss").Inc()
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	cc := cacheControl(resp.Header)
+	if _, noStore := cc["no-store"]; noStore || resp.Header.Get("Vary") == "*" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	stored := &cachedResponse{
+		Status:       resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ResponseTime: time.Now(),
+	}
+	for _, field := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" || clientVaried[name] {
+				continue
+			}
+			if stored.Vary == nil {
+				stored.Vary = make(map[string]string)
+			}
+			stored.Vary[name] = req.Header.Get(name)
+		}
+	}
+	t.save(req.Context(), req, stored)
+	return resp, nil
+}
+
+// save stores cr if it is worth keeping: fresh for a while, or revalidatable.
+func (t *cacheTransport) save(ctx context.Context, req *http.Request, cr *cachedResponse) {
+	ttl := freshnessLifetime(cr.Header, cr.ResponseTime)
+	if cr.hasValidators() {
+		ttl += staleRetention
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(cr)
+	if err != nil {
+		return
+	}
+	if err := t.store.Set(context.WithoutCancel(ctx), cacheKey(req), data, ttl); err != nil {
+		t.log.Debug("httpclient: falha ao gravar cache HTTP", "url", req.URL.Redacted(), "err", err)
+	}
+}
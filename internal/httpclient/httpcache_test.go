@@ -0,0 +1,262 @@
+package httpclient
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memCache is a CacheStore that remembers the TTL of each entry.
+type memCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newMemCache() *memCache {
+	return &memCache{data: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	return v, ok, nil
+}
+
+func (m *memCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key], m.ttls[key] = value, ttl
+	return nil
+}
+
+func newCacheClient(t *testing.T, cache CacheStore) *Client {
+	t.Helper()
+	c, err := New(Options{
+		MinDelay:         time.Microsecond,
+		MaxDelay:         time.Microsecond,
+		IgnoreRobots:     true,
+		BreakerThreshold: -1,
+		HTTPCache:        cache,
+		Logger:           discardLog,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// fetch does a GET and returns the status and body.
+func fetch(t *testing.T, c *Client, method, url string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestHTTPCache(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		header   map[string]string
+		wantHits int32 // requests que chegam ao servidor, de 3
+	}{
+		{name: "max-age", header: map[string]string{"Cache-Control": "max-age=60"}, wantHits: 1},
+		{name: "expired max-age", header: map[string]string{"Cache-Control": "max-age=0"}, wantHits: 3},
+		{
+			name: "expires",
+			header: map[string]string{
+				"Date":    now.Format(http.TimeFormat),
+				"Expires": now.Add(time.Minute).Format(http.TimeFormat),
+			},
+			wantHits: 1,
+		},
+		{
+			name: "max-age beats expires",
+			header: map[string]string{
+				"Cache-Control": "max-age=0",
+				"Expires":       now.Add(time.Hour).Format(http.TimeFormat),
+			},
+			wantHits: 3,
+		},
+		{name: "bad expires", header: map[string]string{"Expires": "0"}, wantHits: 3},
+		{
+			name:     "heuristic from last-modified",
+			header:   map[string]string{"Last-Modified": now.Add(-10 * 24 * time.Hour).Format(http.TimeFormat)},
+			wantHits: 1,
+		},
+		{name: "no freshness", wantHits: 3},
+		{name: "no-store", header: map[string]string{"Cache-Control": "no-store, max-age=60"}, wantHits: 3},
+		{name: "vary star", header: map[string]string{"Cache-Control": "max-age=60", "Vary": "*"}, wantHits: 3},
+		{
+			// O User-Agent muda a cada request e o Accept-Encoding é do transport.
+			name:     "vary on client headers",
+			header:   map[string]string{"Cache-Control": "max-age=60", "Vary": "User-Agent, Accept-Encoding"},
+			wantHits: 1,
+		},
+		{
+			name:     "vary on a fixed header",
+			header:   map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Language"},
+			wantHits: 1,
+		},
+		{name: "not ok", status: http.StatusNotFound, header: map[string]string{"Cache-Control": "max-age=60"}, wantHits: 3},
+		{name: "post", method: http.MethodPost, header: map[string]string{"Cache-Control": "max-age=60"}, wantHits: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(cmp.Or(tt.status, http.StatusOK))
+				io.WriteString(w, "corpo")
+			}))
+			defer srv.Close()
+
+			c := newCacheClient(t, newMemCache())
+			for i := range 3 {
+				status, body := fetch(t, c, cmp.Or(tt.method, http.MethodGet), srv.URL+"/vagas")
+				if status != cmp.Or(tt.status, http.StatusOK) || body != "corpo" {
+					t.Errorf("request %d = %d %q, want %d %q", i, status, body, cmp.Or(tt.status, http.StatusOK), "corpo")
+				}
+			}
+			if n := hits.Load(); n != tt.wantHits {
+				t.Errorf("server got %d requests, want %d", n, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestHTTPCacheRevalidation(t *testing.T) {
+	lastModified := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		name      string
+		validator string // header da resposta
+		value     string
+		condition string // header esperado na revalidação
+	}{
+		{"etag", "ETag", `"v1"`, "If-None-Match"},
+		{"last-modified", "Last-Modified", lastModified, "If-Modified-Since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits, revalidated atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				if r.Header.Get(tt.condition) == tt.value {
+					// O 304 renova a validade da resposta guardada.
+					revalidated.Add(1)
+					w.Header().Set("Cache-Control", "max-age=60")
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Header().Set(tt.validator, tt.value)
+				io.WriteString(w, "corpo")
+			}))
+			defer srv.Close()
+
+			c := newCacheClient(t, newMemCache())
+			for i := range 4 {
+				status, body := fetch(t, c, http.MethodGet, srv.URL)
+				if status != http.StatusOK || body != "corpo" {
+					t.Errorf("request %d = %d %q, want the stored body", i, status, body)
+				}
+			}
+			// Baixa, revalida uma vez e passa a servir do cache.
+			if h, r := hits.Load(), revalidated.Load(); h != 2 || r != 1 {
+				t.Errorf("server got %d requests, %d conditional, want 2 and 1", h, r)
+			}
+		})
+	}
+}
+
+func TestCachedResponseMatches(t *testing.T) {
+	cr := &cachedResponse{Vary: map[string]string{
+		"Accept-Language": "pt-BR",
+		"User-Agent":      "Chrome",
+		"Accept-Encoding": "",
+	}}
+	tests := []struct {
+		header map[string]string
+		want   bool
+	}{
+		{map[string]string{"Accept-Language": "pt-BR", "User-Agent": "Chrome"}, true},
+		{map[string]string{"Accept-Language": "pt-BR", "User-Agent": "Firefox", "Accept-Encoding": "gzip"}, true},
+		{map[string]string{"Accept-Language": "en"}, false},
+		{map[string]string{}, false},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, "http://site.test/", nil)
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		if got := cr.matches(req); got != tt.want {
+			t.Errorf("matches(%v) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPCacheSave(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration // 0: não guarda
+	}{
+		{"max-age", http.Header{"Cache-Control": {"max-age=60"}}, time.Minute},
+		{"max-age with etag", http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}, time.Minute + staleRetention},
+		{"no-cache with etag", http.Header{"Cache-Control": {"no-cache"}, "Etag": {`"v1"`}}, staleRetention},
+		{
+			"heuristic",
+			http.Header{
+				"Date":          {now.Format(http.TimeFormat)},
+				"Last-Modified": {now.Add(-10 * time.Hour).Format(http.TimeFormat)},
+			},
+			time.Hour + staleRetention,
+		},
+		{
+			"heuristic capped",
+			http.Header{
+				"Date":          {now.Format(http.TimeFormat)},
+				"Last-Modified": {now.Add(-30 * 24 * time.Hour).Format(http.TimeFormat)},
+			},
+			heuristicMax + staleRetention,
+		},
+		{
+			"expires",
+			http.Header{"Date": {now.Format(http.TimeFormat)}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}},
+			time.Hour,
+		},
+		{"past expires", http.Header{"Expires": {now.Add(-time.Hour).Format(http.TimeFormat)}}, 0},
+		{"no freshness", http.Header{}, 0},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache, max-age=60"}}, 0},
+	}
+	for _, tt := range tests {
+		cache := newMemCache()
+		tr := &cacheTransport{store: cache, log: discardLog}
+		req, _ := http.NewRequest(http.MethodGet, "http://site.test/", nil)
+		tr.save(context.Background(), req, &cachedResponse{Status: 200, Header: tt.header, ResponseTime: now})
+
+		got, stored := cache.ttls[cacheKey(req)]
+		if stored != (tt.want > 0) || !near(got, tt.want, time.Second) {
+			t.Errorf("%s: saved with TTL %v (stored %v), want %v", tt.name, got, stored, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Proxy rotation modes.
+const (
+	RotatePerRequest = "request" // cada request usa o próximo proxy
+	RotatePerHost    = "host"    // cada host fica com o mesmo proxy até ele falhar
+)
+
+// proxyKey is the context key holding the proxy chosen for a request.
+type proxyKey struct{}
+
+type proxyEntry struct {
+	url       *url.URL
+	downUntil time.Time // fora da rotação até esse momento
+}
+
+// proxyPool rotates requests across proxies and takes out of the rotation,
+// for cooldown, every proxy that fails or gets a 403/429.
+type proxyPool struct {
+	rotation string
+	cooldown time.Duration
+	log      *slog.Logger
+
+	mu      sync.Mutex
+	proxies []*proxyEntry
+	next    int
+	byHost  map[string]*proxyEntry
+}
+
+func newProxyPool(rawURLs []string, rotation string, cooldown time.Duration, log *slog.Logger) (*proxyPool, error) {
+	switch rotation {
+	case "":
+		rotation = RotatePerRequest
+	case RotatePerRequest, RotatePerHost:
+	default:
+		return nil, fmt.Errorf("httpclient: invalid proxy rotation %q (use %q or %q)", rotation, RotatePerRequest, RotatePerHost)
+	}
+
+	p := &proxyPool{
+		rotation: rotation,
+		cooldown: cooldown,
+		log:      log,
+		byHost:   make(map[string]*proxyEntry),
+	}
+	for _, raw := range rawURLs {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("httpclient: invalid proxy URL: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("httpclient: unsupported proxy scheme %q in %s", u.Scheme, u.Redacted())
+		}
+		p.proxies = append(p.proxies, &proxyEntry{url: u})
+	}
+	return p, nil
+}
+
+// pick chooses the proxy for a request to host. When every proxy is out of
+// the rotation, the one that comes back first is used anyway: a degraded
+// proxy is better than failing the search.
+func (p *proxyPool) pick(host string) *proxyEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+
+	if p.rotation == RotatePerHost {
+		if e, ok := p.byHost[host]; ok && !now.Before(e.downUntil) {
+			return e
+		}
+	}
+
+	var chosen *proxyEntry
+	for i := range p.proxies {
+		e := p.proxies[(p.next+i)%len(p.proxies)]
+		if !now.Before(e.downUntil) {
+			chosen = e
+			p.next = (p.next + i + 1) % len(p.proxies)
+			break
+		}
+	}
+	if chosen == nil {
+		chosen = p.proxies[0]
+		for _, e := range p.proxies[1:] {
+			if e.downUntil.Before(chosen.downUntil) {
+				chosen = e
+			}
+		}
+		p.log.Warn("httpclient: todos os proxies fora da rotação, usando o próximo a voltar", "proxy", chosen.url.Redacted())
+	}
+
+	if p.rotation == RotatePerHost {
+		p.byHost[host] = chosen
+	}
+	return chosen
+}
+
+// fail takes the proxy out of the rotation for the cool-down.
+func (p *proxyPool) fail(e *proxyEntry, reason string) {
+	p.mu.Lock()
+	e.downUntil = time.Now().Add(p.cooldown)
+	p.mu.Unlock()
+	p.log.Warn("httpclient: proxy removido da rotação", "proxy", e.url.Redacted(), "reason", reason, "cooldown", p.cooldown)
+}
+
+// proxyFunc is the http.Transport Proxy hook: it returns the proxy that
+// Client.do stored in the request context.
+func proxyFunc(req *http.Request) (*url.URL, error) {
+	if e, ok := req.Context().Value(proxyKey{}).(*proxyEntry); ok {
+		return e.url, nil
+	}
+	return nil, nil
+}
+
+// withProxy returns a shallow copy of req that will be sent through e.
+func withProxy(req *http.Request, e *proxyEntry) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), proxyKey{}, e))
+}
+
+// ServedBy returns the proxy (with the password redacted) that served resp,
+// or "" if the request went direct.
+func ServedBy(resp *http.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
+	}
+	if e, ok := resp.Request.Context().Value(proxyKey{}).(*proxyEntry); ok {
+		return e.url.Redacted()
+	}
+	return ""
+}
+
+// LoadProxyFile reads proxy URLs from a file, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadProxyFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: opening proxy file: %w", err)
+	}
+	defer f.Close()
+
+	var urls []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("httpclient: reading proxy file: %w", err)
+	}
+	return urls, nil
+}
@@ -0,0 +1,224 @@
+package httpclient
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProxy is an HTTP forward proxy that answers every request itself,
+// with the status it is set to, and counts the hosts it was asked for.
+type fakeProxy struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu    sync.Mutex
+	hosts []string
+}
+
+func newFakeProxy(t *testing.T) *fakeProxy {
+	p := &fakeProxy{}
+	p.status.Store(http.StatusOK)
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.hosts = append(p.hosts, r.URL.Host) // pedido de proxy: URL absoluta
+		p.mu.Unlock()
+		w.WriteHeader(int(p.status.Load()))
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakeProxy) served() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.hosts)
+}
+
+// proxyClient returns a client over the proxies, with no delays, robots.txt
+// or circuit breaker in the way.
+func proxyClient(t *testing.T, rotation string, cooldown time.Duration, retries int, proxies ...*fakeProxy) *Client {
+	t.Helper()
+	var urls []string
+	for _, p := range proxies {
+		urls = append(urls, p.URL)
+	}
+	c, err := New(Options{
+		Proxies:          urls,
+		ProxyRotation:    rotation,
+		ProxyCooldown:    cooldown,
+		MinDelay:         time.Microsecond,
+		MaxDelay:         time.Microsecond,
+		MaxRetries:       retries,
+		RetryBase:        time.Millisecond,
+		IgnoreRobots:     true,
+		BreakerThreshold: -1,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// get requests rawURL and returns the proxy that served it and the status.
+func get(t *testing.T, c *Client, rawURL string) (string, int) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+	return ServedBy(resp), resp.StatusCode
+}
+
+func TestProxyRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation string
+		urls     []string
+		want     []int // índice do proxy que atende cada request
+	}{
+		{
+			name:     "per request",
+			rotation: RotatePerRequest,
+			urls:     []string{"http://a.test/1", "http://a.test/2", "http://a.test/3", "http://b.test/1"},
+			want:     []int{0, 1, 0, 1},
+		},
+		{
+			name:     "default is per request",
+			rotation: "",
+			urls:     []string{"http://a.test/1", "http://a.test/2"},
+			want:     []int{0, 1},
+		},
+		{
+			name:     "per host",
+			rotation: RotatePerHost,
+			urls:     []string{"http://a.test/1", "http://b.test/1", "http://a.test/2", "http://b.test/2", "http://a.test/3"},
+			want:     []int{0, 1, 0, 1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxies := []*fakeProxy{newFakeProxy(t), newFakeProxy(t)}
+			c := proxyClient(t, tt.rotation, time.Minute, 1, proxies...)
+
+			for i, u := range tt.urls {
+				got, status := get(t, c, u)
+				if status != http.StatusOK {
+					t.Fatalf("GET %s: status %d", u, status)
+				}
+				if want := proxies[tt.want[i]].URL; got != want {
+					t.Errorf("GET %s served by %s, want %s", u, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProxyRemovedAfterBlock(t *testing.T) {
+	for _, status := range []int{http.StatusForbidden, http.StatusTooManyRequests} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			a, b := newFakeProxy(t), newFakeProxy(t)
+			a.status.Store(int32(status))
+			c := proxyClient(t, RotatePerRequest, time.Minute, 2, a, b)
+
+			// A bloqueia: a nova tentativa sai pelo B.
+			got, code := get(t, c, "http://a.test/1")
+			if got != b.URL || code != http.StatusOK {
+				t.Errorf("first request served by %s with %d, want %s with 200", got, code, b.URL)
+			}
+			// A fica fora da rotação durante o cooldown.
+			for i := range 3 {
+				if got, _ := get(t, c, "http://a.test/2"); got != b.URL {
+					t.Errorf("request %d served by %s, want %s", i, got, b.URL)
+				}
+			}
+			if n := a.served(); n != 1 {
+				t.Errorf("blocked proxy got %d requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestProxyReadmittedAfterCooldown(t *testing.T) {
+	a, b := newFakeProxy(t), newFakeProxy(t)
+	a.status.Store(http.StatusForbidden)
+	c := proxyClient(t, RotatePerHost, 50*time.Millisecond, 1, a, b)
+
+	if got, code := get(t, c, "http://a.test/1"); got != a.URL || code != http.StatusForbidden {
+		t.Fatalf("first request served by %s with %d, want %s with 403", got, code, a.URL)
+	}
+	if got, _ := get(t, c, "http://a.test/2"); got != b.URL {
+		t.Errorf("during cooldown served by %s, want %s", got, b.URL)
+	}
+
+	a.status.Store(http.StatusOK)
+	b.status.Store(http.StatusTooManyRequests)
+	time.Sleep(60 * time.Millisecond)
+
+	// B sai da rotação; A, de volta após o cooldown, assume o host.
+	get(t, c, "http://a.test/3")
+	if got, code := get(t, c, "http://a.test/4"); got != a.URL || code != http.StatusOK {
+		t.Errorf("after cooldown served by %s with %d, want %s with 200", got, code, a.URL)
+	}
+}
+
+func TestProxyAllDown(t *testing.T) {
+	a, b := newFakeProxy(t), newFakeProxy(t)
+	a.status.Store(http.StatusForbidden)
+	b.status.Store(http.StatusForbidden)
+	c := proxyClient(t, RotatePerRequest, time.Minute, 1, a, b)
+
+	get(t, c, "http://a.test/1")
+	get(t, c, "http://a.test/2")
+	// Todos fora da rotação: usa o que volta primeiro em vez de falhar.
+	if got, _ := get(t, c, "http://a.test/3"); got != a.URL {
+		t.Errorf("served by %s, want %s (first back)", got, a.URL)
+	}
+}
+
+func TestServedBy(t *testing.T) {
+	p := newFakeProxy(t)
+	u, _ := url.Parse(p.URL)
+	u.User = url.UserPassword("user", "s3cret")
+
+	c, err := New(Options{
+		ProxyURL:         u.String(),
+		MinDelay:         time.Microsecond,
+		MaxDelay:         time.Microsecond,
+		IgnoreRobots:     true,
+		BreakerThreshold: -1,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := get(t, c, "http://a.test/1")
+	if strings.Contains(got, "s3cret") || !strings.Contains(got, "user:xxxxx@") {
+		t.Errorf("ServedBy = %q, want the proxy with the password redacted", got)
+	}
+
+	// Sem proxy, a resposta vem direto.
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer direct.Close()
+	resp, err := direct.Client().Get(direct.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := ServedBy(resp); got != "" {
+		t.Errorf("ServedBy of a direct response = %q, want empty", got)
+	}
+	if got := ServedBy(nil); got != "" {
+		t.Errorf("ServedBy(nil) = %q, want empty", got)
+	}
+}
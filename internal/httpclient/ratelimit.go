@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter spaces requests per host. Implementations must be safe for
+// concurrent use: two callers never get the same slot.
+type Limiter interface {
+	// Reserve books the next slot for host, given the spacing between
+	// requests and how many may go out back to back, and returns how long
+	// the caller must wait before sending.
+	Reserve(ctx context.Context, host string, interval time.Duration, burst int) (time.Duration, error)
+}
+
+// MemoryLimiter is an in-process GCRA (generic cell rate algorithm)
+// limiter: a token bucket that stores one timestamp per host.
+type MemoryLimiter struct {
+	mu  sync.Mutex
+	tat map[string]time.Time // theoretical arrival time da próxima request
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{tat: make(map[string]time.Time)}
+}
+
+func (l *MemoryLimiter) Reserve(_ context.Context, host string, interval time.Duration, burst int) (time.Duration, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tat := l.tat[host]
+	if tat.Before(now) {
+		tat = now
+	}
+	l.tat[host] = tat.Add(interval)
+
+	allowAt := tat.Add(-interval * time.Duration(max(burst, 1)-1))
+	return max(allowAt.Sub(now), 0), nil
+}
+
+// gcraScript is the same algorithm as MemoryLimiter, run atomically in
+// Redis with the server clock, so every instance shares one budget.
+var gcraScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+local new_tat = tat + interval
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000) + 1000)
+
+local wait = tat - interval * (burst - 1) - now
+if wait < 0 then wait = 0 end
+return wait
+`)
+
+// RedisLimiter is a GCRA limiter whose state lives in Redis, for several
+// go-work instances behind the same IP.
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter returns a limiter over client, which the caller closes.
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (l *RedisLimiter) Reserve(ctx context.Context, host string, interval time.Duration, burst int) (time.Duration, error) {
+	wait, err := gcraScript.Run(ctx, l.client, []string{"gowork:ratelimit:" + host},
+		interval.Microseconds(), max(burst, 1)).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("httpclient: redis rate limit: %w", err)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
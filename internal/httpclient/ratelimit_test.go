@@ -0,0 +1,140 @@
+package httpclient
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// limiters returns a fresh instance of every Limiter; the Redis one runs
+// the GCRA script on miniredis.
+func limiters(t *testing.T) map[string]Limiter {
+	_, client := newMiniredis(t)
+	return map[string]Limiter{
+		"memory": NewMemoryLimiter(),
+		"redis":  NewRedisLimiter(client),
+	}
+}
+
+// near reports whether got is want, give or take tol.
+func near(got, want, tol time.Duration) bool {
+	return got >= want-tol && got <= want+tol
+}
+
+func TestLimiterConcurrent(t *testing.T) {
+	const (
+		n        = 30
+		burst    = 3
+		interval = 10 * time.Second
+	)
+	for name, l := range limiters(t) {
+		t.Run(name, func(t *testing.T) {
+			var (
+				wg     sync.WaitGroup
+				mu     sync.Mutex
+				delays []time.Duration
+			)
+			for range n {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					d, err := l.Reserve(context.Background(), "site.test", interval, burst)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					delays = append(delays, d)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+			slices.Sort(delays)
+			if len(delays) != n {
+				t.Fatalf("got %d delays, want %d", len(delays), n)
+			}
+
+			// As primeiras burst saem já; cada uma das demais tem seu próprio
+			// horário, interval depois da anterior.
+			for i, d := range delays {
+				want := time.Duration(max(i-burst+1, 0)) * interval
+				if !near(d, want, time.Second) {
+					t.Errorf("delay %d = %v, want %v", i, d, want)
+				}
+				if i > burst-1 && d-delays[i-1] < interval-time.Second {
+					t.Errorf("delays %d and %d are %v apart, want %v", i-1, i, d-delays[i-1], interval)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	const interval = 40 * time.Millisecond
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		burst int
+		idle  time.Duration // pausa depois das primeiras reservas
+		want  []time.Duration
+	}{
+		{"burst admits back to back", 3, 0, []time.Duration{0, 0, 0, interval, 2 * interval}},
+		{"zero burst means one", 0, 0, []time.Duration{0, interval, 2 * interval}},
+		{"one", 1, 0, []time.Duration{0, interval}},
+		{"refills after idle", 2, 5 * interval, []time.Duration{0, 0, interval, 0, 0, interval}},
+	}
+	for _, tt := range tests {
+		for name, l := range limiters(t) {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				for i, want := range tt.want {
+					if tt.idle > 0 && i == len(tt.want)/2 {
+						time.Sleep(tt.idle)
+					}
+					got, err := l.Reserve(ctx, "site.test", interval, tt.burst)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !near(got, want, interval/4) {
+						t.Errorf("reserve %d = %v, want %v", i, got, want)
+					}
+				}
+				// Cada host tem seu próprio orçamento.
+				if got, _ := l.Reserve(ctx, "other.test", interval, tt.burst); got != 0 {
+					t.Errorf("other host waits %v, want 0", got)
+				}
+			})
+		}
+	}
+}
+
+// Duas instâncias no mesmo Redis dividem o orçamento do host.
+func TestRedisLimiterShared(t *testing.T) {
+	const interval = 10 * time.Second
+	ctx := context.Background()
+	mr, client := newMiniredis(t)
+	other := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer other.Close()
+
+	instances := []*RedisLimiter{NewRedisLimiter(client), NewRedisLimiter(other)}
+	for i := range 6 {
+		got, err := instances[i%2].Reserve(ctx, "site.test", interval, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := time.Duration(max(i-1, 0)) * interval
+		if !near(got, want, time.Second) {
+			t.Errorf("reserve %d (instance %d) = %v, want %v", i, i%2, got, want)
+		}
+	}
+
+	// A chave expira quando o host fica ocioso.
+	ttl := mr.TTL("gowork:ratelimit:site.test")
+	if ttl <= 0 || ttl > 6*interval+2*time.Second {
+		t.Errorf("TTL = %v, want until the last slot", ttl)
+	}
+}
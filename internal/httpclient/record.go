@@ -0,0 +1,192 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNotRecorded is matched (via errors.Is) when replay mode has no
+// recording for a request.
+var ErrNotRecorded = errors.New("httpclient: request not recorded")
+
+// volatileHeaders change on every request or identify a session; they are
+// dropped from recordings so the files diff cleanly and leak no secrets.
+var volatileHeaders = []string{
+	"Age", "Alt-Svc", "Authorization", "Cf-Ray", "Content-Length", "Cookie", "Date",
+	"Expires", "Nel", "Proxy-Authorization", "Report-To", "Server-Timing",
+	"Set-Cookie", "User-Agent", "X-Amz-Cf-Id", "X-Request-Id",
+}
+
+// exchange is one recorded request/response pair, stored as JSON.
+type exchange struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	// Body holds text responses; binary ones go base64-encoded in BodyBase64.
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+// recordTransport sends requests through next and saves every exchange
+// to dir, one JSON file per distinct request.
+type recordTransport struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func newRecordTransport(next http.RoundTripper, dir string) (*recordTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("httpclient: creating record dir: %w", err)
+	}
+	return &recordTransport{next: next, dir: dir}, nil
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: reading response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	ex := exchange{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: scrub(req.Header),
+			Body:   string(reqBody),
+		},
+		Response: recordedResponse{Status: resp.StatusCode, Header: scrub(resp.Header)},
+	}
+	if utf8.Valid(respBody) {
+		ex.Response.Body = string(respBody)
+	} else {
+		ex.Response.BodyBase64 = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	data, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: encoding recording: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, recordName(req, reqBody)), data, 0o644); err != nil {
+		return nil, fmt.Errorf("httpclient: writing recording: %w", err)
+	}
+	return resp, nil
+}
+
+// replayTransport serves the exchanges saved by recordTransport, without
+// touching the network.
+type replayTransport struct {
+	dir string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(t.dir, recordName(req, reqBody)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL.Redacted())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: reading recording: %w", err)
+	}
+
+	var ex exchange
+	if err := json.Unmarshal(data, &ex); err != nil {
+		return nil, fmt.Errorf("httpclient: decoding recording for %s: %w", req.URL.Redacted(), err)
+	}
+
+	body := []byte(ex.Response.Body)
+	if ex.Response.BodyBase64 != "" {
+		if body, err = base64.StdEncoding.DecodeString(ex.Response.BodyBase64); err != nil {
+			return nil, fmt.Errorf("httpclient: decoding recorded body for %s: %w", req.URL.Redacted(), err)
+		}
+	}
+
+	header := ex.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Response.Status, http.StatusText(ex.Response.Status)),
+		StatusCode:    ex.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the body for the recording key and puts an
+// identical one back on req.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// recordName is the recording file of a request: the host, for browsing,
+// and a hash of method, URL and body. Headers are left out on purpose, since
+// the User-Agent rotates.
+func recordName(req *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL.String())
+	h.Write(body)
+	host := strings.NewReplacer(":", "_", "/", "_").Replace(req.URL.Host)
+	return fmt.Sprintf("%s_%x.json", host, h.Sum(nil)[:8])
+}
+
+func scrub(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range volatileHeaders {
+		out.Del(name)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
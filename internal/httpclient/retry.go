@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ErrRetriesExhausted is matched (via errors.Is) by every
+// *RetriesExhaustedError.
+var ErrRetriesExhausted = errors.New("httpclient: retries exhausted")
+
+// RetriesExhaustedError is returned when every attempt of a request failed
+// with a retryable error or status.
+type RetriesExhaustedError struct {
+	Attempts   int
+	StatusCode int   // status da última tentativa; 0 se ela falhou na rede
+	Err        error // erro da última tentativa, se falhou na rede
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("httpclient: retries exhausted after %d attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("httpclient: retries exhausted after %d attempts: status %d", e.Attempts, e.StatusCode)
+}
+
+func (e *RetriesExhaustedError) Is(target error) bool {
+	return target == ErrRetriesExhausted
+}
+
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// idempotent reports whether req can be sent again safely, following the
+// same rule as net/http: idempotent methods, or an Idempotency-Key header.
+// A request with a body also needs GetBody to be rewound.
+func idempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	return ok
+}
+
+// retryableStatus reports whether the status means the server is
+// overloaded or temporarily broken.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableErr reports whether a transport error is transient: timeouts,
+// connection resets and refusals, and connections closed mid-response.
+// Cancellation, bad certificates and malformed requests are not retried.
+func retryableErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryAfter parses the Retry-After header, in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// backoff returns a full-jitter delay: random between zero and
+// base * 2^attempt, capped at limit.
+func backoff(attempt int, base, limit time.Duration) time.Duration {
+	ceil := limit
+	if attempt < 30 {
+		ceil = min(base<<uint(attempt), limit)
+	}
+	if ceil <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceil) + 1))
+}
+
+// discard drains and closes a response that won't be returned, so the
+// connection can be reused.
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
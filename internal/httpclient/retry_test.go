@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "120", 120 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"negative", "-5", 0, false},
+		{"future date", time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), 30 * time.Second, true},
+		{"past date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+		{"garbage", "amanhã", 0, false},
+		{"fraction", "1.5", 0, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			resp.Header.Set("Retry-After", tt.header)
+		}
+		got, ok := retryAfter(resp)
+		// A data tem resolução de segundos.
+		if ok != tt.ok || !near(got, tt.want, time.Second) {
+			t.Errorf("%s: retryAfter(%q) = %v, %v, want %v, %v", tt.name, tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	const base, limit = 100 * time.Millisecond, time.Second
+	tests := []struct {
+		attempt int
+		ceil    time.Duration
+	}{
+		{0, base},
+		{1, 2 * base},
+		{3, 8 * base},
+		{4, limit}, // 1.6s, limitado
+		{40, limit},
+	}
+	for _, tt := range tests {
+		var top time.Duration
+		for range 2000 {
+			d := backoff(tt.attempt, base, limit)
+			if d < 0 || d > tt.ceil {
+				t.Fatalf("backoff(%d) = %v, want within [0, %v]", tt.attempt, d, tt.ceil)
+			}
+			top = max(top, d)
+		}
+		// Full jitter: os valores cobrem o intervalo todo, não só o teto.
+		if top < tt.ceil/2 {
+			t.Errorf("backoff(%d) never above %v in 2000 draws, want up to %v", tt.attempt, top, tt.ceil)
+		}
+	}
+	if d := backoff(3, 0, limit); d != 0 {
+		t.Errorf("backoff with zero base = %v, want 0", d)
+	}
+}
+
+// timeoutErr is a net.Error that timed out.
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestRetryableErr(t *testing.T) {
+	opErr := func(err error) error { return &net.OpError{Op: "read", Net: "tcp", Err: err} }
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", timeoutErr{}, true},
+		{"wrapped timeout", &url.Error{Op: "Get", URL: "http://x", Err: timeoutErr{}}, true},
+		{"connection reset", opErr(syscall.ECONNRESET), true},
+		{"connection refused", opErr(syscall.ECONNREFUSED), true},
+		{"broken pipe", opErr(syscall.EPIPE), true},
+		{"eof", fmt.Errorf("reading: %w", io.EOF), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"canceled", &url.Error{Op: "Get", URL: "http://x", Err: context.Canceled}, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"bad certificate", &tls.CertificateVerificationError{Err: errors.New("unknown authority")}, false},
+		{"other", errors.New("unsupported protocol scheme"), false},
+		{"dns without timeout", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+	}
+	for _, tt := range tests {
+		if got := retryableErr(tt.err); got != tt.want {
+			t.Errorf("%s: retryableErr(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	for code, want := range map[int]bool{
+		200: false, 301: false, 400: false, 403: false, 404: false,
+		429: true, 500: true, 501: false, 502: true, 503: true, 504: true,
+	} {
+		if got := retryableStatus(code); got != want {
+			t.Errorf("retryableStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	req := func(method string, body io.Reader, key bool) *http.Request {
+		r, _ := http.NewRequest(method, "http://site.test/", body)
+		if key {
+			r.Header.Set("Idempotency-Key", "abc")
+		}
+		return r
+	}
+	tests := []struct {
+		name string
+		req  *http.Request
+		want bool
+	}{
+		{"get", req(http.MethodGet, nil, false), true},
+		{"head", req(http.MethodHead, nil, false), true},
+		{"put with rewindable body", req(http.MethodPut, strings.NewReader("x"), false), true},
+		{"delete", req(http.MethodDelete, nil, false), true},
+		{"post", req(http.MethodPost, strings.NewReader("x"), false), false},
+		{"post with idempotency key", req(http.MethodPost, strings.NewReader("x"), true), true},
+		// Um corpo que não volta ao início não pode ser reenviado.
+		{"put with one-shot body", req(http.MethodPut, io.MultiReader(strings.NewReader("x")), false), false},
+	}
+	for _, tt := range tests {
+		if got := idempotent(tt.req); got != tt.want {
+			t.Errorf("%s: idempotent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		fails      int    // respostas 503 antes de um 200
+		retryAfter string // header das respostas 503
+		wantHits   int32
+		wantStatus int  // 0: erro
+		exhausted  bool // erro é ErrRetriesExhausted
+	}{
+		{name: "recovers", method: http.MethodGet, fails: 2, wantHits: 3, wantStatus: 200},
+		{name: "exhausted", method: http.MethodGet, fails: 10, wantHits: 3, exhausted: true},
+		{name: "post not retried", method: http.MethodPost, fails: 10, wantHits: 1, wantStatus: 503},
+		{name: "retry-after over the max", method: http.MethodGet, fails: 10, retryAfter: "3600", wantHits: 1, exhausted: true},
+		{name: "honors retry-after", method: http.MethodGet, fails: 1, retryAfter: "0", wantHits: 2, wantStatus: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(hits.Add(1)) <= tt.fails {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				io.WriteString(w, "ok")
+			}))
+			defer srv.Close()
+
+			c, err := New(Options{
+				MinDelay:         time.Microsecond,
+				MaxDelay:         time.Microsecond,
+				MaxRetries:       3,
+				RetryBase:        time.Millisecond,
+				RetryMax:         100 * time.Millisecond,
+				IgnoreRobots:     true,
+				BreakerThreshold: -1,
+				Logger:           discardLog,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, _ := http.NewRequest(tt.method, srv.URL, strings.NewReader("corpo"))
+			resp, err := c.Do(req)
+
+			if n := hits.Load(); n != tt.wantHits {
+				t.Errorf("server got %d requests, want %d", n, tt.wantHits)
+			}
+			if tt.exhausted {
+				if !errors.Is(err, ErrRetriesExhausted) || resp != nil {
+					t.Fatalf("Do = %v, %v, want ErrRetriesExhausted and a nil response", resp, err)
+				}
+				var exhausted *RetriesExhaustedError
+				if !errors.As(err, &exhausted) || exhausted.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("StatusCode = %d, want 503", exhausted.StatusCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// Conexões derrubadas no meio da resposta são repetidas e, esgotadas as
+// tentativas, o erro guarda a causa.
+func TestDoRetriesNetworkError(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	c, err := New(Options{
+		MinDelay:         time.Microsecond,
+		MaxDelay:         time.Microsecond,
+		MaxRetries:       2,
+		RetryBase:        time.Millisecond,
+		IgnoreRobots:     true,
+		BreakerThreshold: -1,
+		Logger:           discardLog,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+
+	var exhausted *RetriesExhaustedError
+	if resp != nil || !errors.As(err, &exhausted) || exhausted.Err == nil || exhausted.Attempts != 2 {
+		t.Fatalf("Do = %v, %v, want retries exhausted after 2 network errors", resp, err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
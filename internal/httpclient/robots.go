@@ -0,0 +1,289 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsAgent is the product token matched against robots.txt User-agent
+// lines. Groups for "*" apply when no group names it.
+const robotsAgent = "go-work"
+
+const (
+	robotsTTL      = 24 * time.Hour
+	robotsRetryTTL = 5 * time.Minute // após erro de rede ou 5xx
+	robotsMaxSize  = 512 << 10
+	robotsTimeout  = 30 * time.Second
+)
+
+// ErrDisallowed is matched (via errors.Is) by every *DisallowedError.
+var ErrDisallowed = errors.New("httpclient: disallowed by robots.txt")
+
+// DisallowedError is returned, without sending the request, when the
+// host's robots.txt disallows the URL.
+type DisallowedError struct {
+	URL string
+}
+
+func (e *DisallowedError) Error() string {
+	return fmt.Sprintf("httpclient: %s disallowed by robots.txt", e.URL)
+}
+
+func (e *DisallowedError) Is(target error) bool {
+	return target == ErrDisallowed
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int // tamanho do padrão original, para a regra mais específica
+	pattern *regexp.Regexp
+}
+
+// newRobotsRule compiles a robots.txt path pattern, where * matches any
+// sequence and a trailing $ anchors the end.
+func newRobotsRule(allow bool, pattern string) robotsRule {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return robotsRule{allow: allow, length: len(pattern), pattern: regexp.MustCompile(expr)}
+}
+
+// robotsRules are the rules of the group that applies to robotsAgent.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// allowed applies the longest matching rule; on a tie Allow wins, and no
+// matching rule means allowed (RFC 9309).
+func (r *robotsRules) allowed(path string) bool {
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > best || (rule.length == best && rule.allow) {
+			best, allow = rule.length, rule.allow
+		}
+	}
+	return allow
+}
+
+// parseRobots extracts the rules for robotsAgent, falling back to the "*"
+// groups. Groups naming the same agent are merged.
+func parseRobots(r io.Reader) *robotsRules {
+	var (
+		own, star robotsRules
+		ownFound  bool
+		// Grupo atual: quais conjuntos ele alimenta, e se já teve regras
+		// (um User-agent depois de regras abre um novo grupo).
+		groupOwn, groupStar, inRules bool
+	)
+	add := func(fn func(*robotsRules)) {
+		if groupOwn {
+			fn(&own)
+		}
+		if groupStar {
+			fn(&star)
+		}
+	}
+
+	sc := bufio.NewScanner(io.LimitReader(r, robotsMaxSize))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				groupOwn, groupStar, inRules = false, false, false
+			}
+			agent := strings.ToLower(value)
+			if agent == "*" {
+				groupStar = true
+			} else if strings.Contains(agent, robotsAgent) {
+				groupOwn, ownFound = true, true
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" vazio libera tudo
+			}
+			rule := newRobotsRule(key == "allow", value)
+			add(func(rr *robotsRules) { rr.rules = append(rr.rules, rule) })
+		case "crawl-delay":
+			inRules = true
+			secs, err := strconv.ParseFloat(value, 64)
+			if err != nil || secs < 0 {
+				continue
+			}
+			d := time.Duration(secs * float64(time.Second))
+			add(func(rr *robotsRules) { rr.crawlDelay = d })
+		}
+	}
+
+	if ownFound {
+		return &own
+	}
+	return &star
+}
+
+type robotsEntry struct {
+	ready   chan struct{} // fechado quando rules estiver pronto
+	rules   *robotsRules
+	expires time.Time
+}
+
+// robotsCache fetches and caches robots.txt per host.
+type robotsCache struct {
+	fetch func(*http.Request) (*http.Response, error)
+	log   *slog.Logger
+
+	mu    sync.Mutex
+	hosts map[string]*robotsEntry
+}
+
+func newRobotsCache(fetch func(*http.Request) (*http.Response, error), log *slog.Logger) *robotsCache {
+	return &robotsCache{fetch: fetch, log: log, hosts: make(map[string]*robotsEntry)}
+}
+
+// check returns a *DisallowedError if u must not be fetched.
+func (rc *robotsCache) check(ctx context.Context, u *url.URL) error {
+	if u.Path == "/robots.txt" {
+		return nil
+	}
+	rules, err := rc.rules(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if !rules.allowed(path) {
+		return &DisallowedError{URL: u.Redacted()}
+	}
+	return nil
+}
+
+// crawlDelay returns the Crawl-delay of host, if its robots.txt was fetched.
+func (rc *robotsCache) crawlDelay(host string) time.Duration {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.hosts[host]
+	if !ok {
+		return 0
+	}
+	select {
+	case <-e.ready:
+		return e.rules.crawlDelay
+	default:
+		return 0
+	}
+}
+
+// rules returns the cached rules for u's host, fetching them once when
+// missing or expired; concurrent callers wait for the same fetch.
+func (rc *robotsCache) rules(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	rc.mu.Lock()
+	e, ok := rc.hosts[u.Host]
+	if ok {
+		select {
+		case <-e.ready:
+			if time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &robotsEntry{ready: make(chan struct{})}
+		rc.hosts[u.Host] = e
+		rc.mu.Unlock()
+
+		e.rules, e.expires = rc.load(ctx, u)
+		close(e.ready)
+		return e.rules, nil
+	}
+	rc.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.rules, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// disallowAll is the rule set of a host whose robots.txt is unreachable.
+func disallowAll() *robotsRules {
+	return &robotsRules{rules: []robotsRule{newRobotsRule(false, "/")}}
+}
+
+// load fetches robots.txt. A 4xx means no restrictions; a network error or
+// 5xx means the site is unreachable, so everything is disallowed until a
+// sooner retry (RFC 9309, section 2.3.1.4).
+func (rc *robotsCache) load(ctx context.Context, u *url.URL) (*robotsRules, time.Time) {
+	// A busca é compartilhada por quem estiver esperando, então não herda o
+	// cancelamento de quem a disparou.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), robotsTimeout)
+	defer cancel()
+
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL.String(), nil)
+	if err != nil {
+		return disallowAll(), time.Now().Add(robotsRetryTTL)
+	}
+
+	resp, err := rc.fetch(req)
+	if errors.Is(err, ErrNotRecorded) {
+		// Gravação sem robots.txt: equivale a não existir, como um 404.
+		return &robotsRules{}, time.Now().Add(robotsTTL)
+	}
+	if err != nil {
+		rc.log.Warn("httpclient: robots.txt inacessível, host bloqueado até nova tentativa", "host", u.Host,
+			"retry_in", robotsRetryTTL, "err", err)
+		return disallowAll(), time.Now().Add(robotsRetryTTL)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		rules := parseRobots(resp.Body)
+		rc.log.Debug("httpclient: robots.txt carregado", "host", u.Host,
+			"rules", len(rules.rules), "crawl_delay", rules.crawlDelay)
+		return rules, time.Now().Add(robotsTTL)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &robotsRules{}, time.Now().Add(robotsTTL)
+	default:
+		rc.log.Warn("httpclient: robots.txt inacessível, host bloqueado até nova tentativa", "host", u.Host,
+			"retry_in", robotsRetryTTL, "status", resp.StatusCode)
+		return disallowAll(), time.Now().Add(robotsRetryTTL)
+	}
+}
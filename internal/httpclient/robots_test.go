@@ -0,0 +1,273 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRobotsRulePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/fish", "/fish", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/fishheads/yummy.html", true},
+		{"/fish", "/Fish.asp", false},
+		{"/fish", "/catfish", false},
+		{"/fish*", "/fish.php?id=1", true},
+		{"/fish/", "/fish/salmon", true},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/folder/filename.php?params", true},
+		{"/*.php", "/", false},
+		{"/*.php$", "/filename.php", true},
+		{"/*.php$", "/filename.php?params", false},
+		{"/*.php$", "/filename.php5", false},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+		{"/a$", "/a", true},
+		{"/a$", "/ab", false},
+		{"/*?", "/search?q=go", true},
+		{"/*?", "/search", false},
+		{"/a+b(c)", "/a+b(c)/x", true},
+		{"/a+b(c)", "/aab", false},
+	}
+	for _, tt := range tests {
+		if got := newRobotsRule(false, tt.pattern).pattern.MatchString(tt.path); got != tt.want {
+			t.Errorf("pattern %q on %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		allowed    []string
+		disallowed []string
+		crawlDelay time.Duration
+	}{
+		{
+			name:       "star group",
+			body:       "User-agent: *\nDisallow: /admin\n",
+			allowed:    []string{"/", "/jobs/1", "/adm"},
+			disallowed: []string{"/admin", "/admin/users"},
+		},
+		{
+			name:       "longest match wins",
+			body:       "User-agent: *\nDisallow: /\nAllow: /public\nDisallow: /public/private\n",
+			allowed:    []string{"/public", "/public/a"},
+			disallowed: []string{"/", "/other", "/public/private/x"},
+		},
+		{
+			name:    "allow wins a tie",
+			body:    "User-agent: *\nDisallow: /page\nAllow: /page\n",
+			allowed: []string{"/page", "/page/2"},
+		},
+		{
+			name:       "wildcards",
+			body:       "User-agent: *\nDisallow: /*.pdf$\nDisallow: /*?\nAllow: /busca?q=*\n",
+			allowed:    []string{"/a.pdf.html", "/vagas", "/busca?q=go"},
+			disallowed: []string{"/doc/a.pdf", "/vagas?page=2"},
+		},
+		{
+			name:       "own group overrides star",
+			body:       "User-agent: *\nDisallow: /\n\nUser-agent: go-work\nDisallow: /private\n",
+			allowed:    []string{"/", "/jobs"},
+			disallowed: []string{"/private"},
+		},
+		{
+			name:       "own agent matched by product token",
+			body:       "USER-AGENT: Go-Work/1.0 # nosso bot\nDISALLOW: /x # comentário\n",
+			disallowed: []string{"/x"},
+			allowed:    []string{"/y"},
+		},
+		{
+			name:       "grouped user-agent lines",
+			body:       "User-agent: googlebot\nUser-agent: go-work\nDisallow: /x\n\nUser-agent: *\nDisallow: /\n",
+			allowed:    []string{"/", "/y"},
+			disallowed: []string{"/x"},
+		},
+		{
+			name:       "group shared with star",
+			body:       "User-agent: other\nUser-agent: *\nDisallow: /y\n",
+			allowed:    []string{"/x"},
+			disallowed: []string{"/y"},
+		},
+		{
+			name: "groups of the same agent merged",
+			body: "User-agent: go-work\nDisallow: /a\n\nUser-agent: other\nDisallow: /\n\n" +
+				"User-agent: go-work\nDisallow: /b\n",
+			allowed:    []string{"/", "/c"},
+			disallowed: []string{"/a", "/b"},
+		},
+		{
+			name:    "empty disallow allows everything",
+			body:    "User-agent: *\nDisallow:\n",
+			allowed: []string{"/", "/admin"},
+		},
+		{
+			name:       "crawl-delay",
+			body:       "User-agent: *\nCrawl-delay: 2.5\nDisallow: /tmp\n",
+			allowed:    []string{"/"},
+			disallowed: []string{"/tmp"},
+			crawlDelay: 2500 * time.Millisecond,
+		},
+		{
+			name:       "crawl-delay of the own group",
+			body:       "User-agent: *\nCrawl-delay: 10\n\nUser-agent: go-work\nCrawl-delay: 1\n",
+			allowed:    []string{"/"},
+			crawlDelay: time.Second,
+		},
+		{
+			name:    "bad crawl-delay ignored",
+			body:    "User-agent: *\nCrawl-delay: logo\nCrawl-delay: -1\n",
+			allowed: []string{"/"},
+		},
+		{
+			name:    "rules outside any group ignored",
+			body:    "Disallow: /\nUser-agent: *\nAllow: /\n",
+			allowed: []string{"/"},
+		},
+		{
+			name:    "no rules",
+			body:    "",
+			allowed: []string{"/", "/anything"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRobots(strings.NewReader(tt.body))
+			for _, p := range tt.allowed {
+				if !rules.allowed(p) {
+					t.Errorf("%s disallowed, want allowed", p)
+				}
+			}
+			for _, p := range tt.disallowed {
+				if rules.allowed(p) {
+					t.Errorf("%s allowed, want disallowed", p)
+				}
+			}
+			if rules.crawlDelay != tt.crawlDelay {
+				t.Errorf("crawl-delay = %v, want %v", rules.crawlDelay, tt.crawlDelay)
+			}
+		})
+	}
+}
+
+func TestRobotsCheck(t *testing.T) {
+	const robots = "User-agent: *\nDisallow: /private\nDisallow: /*?sort=\nCrawl-delay: 1\n"
+
+	tests := []struct {
+		name       string
+		robots     func(w http.ResponseWriter) // resposta do /robots.txt
+		allowed    []string
+		disallowed []string
+	}{
+		{
+			name:       "rules",
+			robots:     func(w http.ResponseWriter) { io.WriteString(w, robots) },
+			allowed:    []string{"/", "/jobs?page=2"},
+			disallowed: []string{"/private/1", "/jobs?sort=date"},
+		},
+		{
+			name:    "missing means no restrictions",
+			robots:  func(w http.ResponseWriter) { http.NotFound(w, nil) },
+			allowed: []string{"/", "/private/1"},
+		},
+		{
+			name:       "server error disallows everything",
+			robots:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			disallowed: []string{"/", "/jobs"},
+		},
+		{
+			name: "network error disallows everything",
+			robots: func(w http.ResponseWriter) {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			},
+			disallowed: []string{"/", "/jobs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var robotsHits, pageHits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					robotsHits.Add(1)
+					tt.robots(w)
+					return
+				}
+				pageHits.Add(1)
+				io.WriteString(w, "ok")
+			}))
+			defer srv.Close()
+
+			c, err := New(Options{
+				MinDelay:         time.Microsecond,
+				MaxDelay:         time.Microsecond,
+				MaxRetries:       1,
+				BreakerThreshold: -1,
+				Logger:           discardLog,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			get := func(path string) error {
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+				resp, err := c.Do(req)
+				if err == nil {
+					resp.Body.Close()
+				}
+				return err
+			}
+
+			for _, p := range tt.allowed {
+				if err := get(p); err != nil {
+					t.Errorf("GET %s = %v, want allowed", p, err)
+				}
+			}
+			for _, p := range tt.disallowed {
+				err := get(p)
+				var disallowed *DisallowedError
+				if !errors.As(err, &disallowed) || !errors.Is(err, ErrDisallowed) || disallowed.URL != srv.URL+p {
+					t.Errorf("GET %s = %v, want a *DisallowedError", p, err)
+				}
+			}
+			if n := pageHits.Load(); int(n) != len(tt.allowed) {
+				t.Errorf("server got %d page requests, want %d: disallowed URLs must not be sent", n, len(tt.allowed))
+			}
+			// O robots.txt é baixado uma vez e fica em cache.
+			if n := robotsHits.Load(); n != 1 {
+				t.Errorf("robots.txt fetched %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestRobotsCrawlDelay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "User-agent: *\nCrawl-delay: 3\n")
+	}))
+	defer srv.Close()
+
+	rc := newRobotsCache(http.DefaultClient.Do, discardLog)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/jobs", nil)
+	if d := rc.crawlDelay(req.URL.Host); d != 0 {
+		t.Errorf("crawl-delay before fetch = %v, want 0", d)
+	}
+	if err := rc.check(req.Context(), req.URL); err != nil {
+		t.Fatal(err)
+	}
+	if d := rc.crawlDelay(req.URL.Host); d != 3*time.Second {
+		t.Errorf("crawl-delay = %v, want 3s", d)
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Registry holds every go-work metric. A dedicated registry (instead of the
+// Prometheus default) keeps pushes and dumps limited to our own series.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts responses per host and status code; transport
+	// failures are recorded with status "error".
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_http_requests_total",
+		Help: "HTTP requests made by httpclient, by host and status code.",
+	}, []string{"host", "status"})
+
+	// HTTPRetries counts retries per host and the status that caused them.
+	HTTPRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_http_retries_total",
+		Help: "HTTP retries made by httpclient, by host and triggering status.",
+	}, []string{"host", "status"})
+
+	// RateLimitWait observes how long requests waited for the per-host rate limit.
+	RateLimitWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gowork_http_rate_limit_wait_seconds",
+		Help:    "Time spent waiting for the per-host rate limit.",
+		Buckets: []float64{0, 0.5, 1, 2, 3, 5, 10, 30},
+	}, []string{"host"})
+
+	// CircuitState reports the circuit breaker state per host: 0 closed,
+	// 1 half-open, 2 open.
+	CircuitState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gowork_http_circuit_state",
+		Help: "Circuit breaker state per host (0 closed, 1 half-open, 2 open).",
+	}, []string{"host"})
+
+	// HTTPCacheRequests counts HTTP response cache lookups, labeled hit,
+	// revalidated (304) or miss.
+	HTTPCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_http_cache_requests_total",
+		Help: "HTTP response cache lookups, by host and result (hit, revalidated or miss).",
+	}, []string{"host", "result"})
+
+	// ScrapeDuration observes each scraper search, labeled ok or error.
+	ScrapeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gowork_scrape_duration_seconds",
+		Help:    "Duration of scraper searches, by scraper and result.",
+		Buckets: prometheus.ExponentialBuckets(0.25, 2, 9),
+	}, []string{"scraper", "result"})
+
+	// ScrapeJobs counts jobs returned by scrapers, before dedup and filters.
+	ScrapeJobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_scrape_jobs_total",
+		Help: "Jobs returned by scrapers, before dedup and filters.",
+	}, []string{"scraper"})
+
+	// CacheRequests counts job cache lookups, labeled hit, stale, negative
+	// (a cached failure) or miss.
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_cache_requests_total",
+		Help: "Job cache lookups, by scraper and result (hit, stale, negative or miss).",
+	}, []string{"scraper", "result"})
+
+	// WriterSends counts deliveries to result writers, labeled ok or error.
+	WriterSends = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowork_writer_sends_total",
+		Help: "Result writer deliveries, by writer and result.",
+	}, []string{"writer", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequests, HTTPRetries, RateLimitWait, CircuitState, HTTPCacheRequests,
+		ScrapeDuration, ScrapeJobs, CacheRequests, WriterSends,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Result returns the "ok"/"error" label for err.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Push sends the current values to a Prometheus Pushgateway, for one-shot
+// runs that exit before any scrape could happen.
+func Push(gatewayURL, job string) error {
+	if err := push.New(gatewayURL, job).Gatherer(Registry).Push(); err != nil {
+		return fmt.Errorf("metrics: push to %s: %w", gatewayURL, err)
+	}
+	return nil
+}
+
+// Dump writes the current values in the text exposition format to path,
+// or to stdout when path is "-".
+func Dump(path string) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("metrics: creating %s: %w", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	families, err := Registry.Gather()
+	if err != nil {
+		return fmt.Errorf("metrics: gather: %w", err)
+	}
+	enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return fmt.Errorf("metrics: encode: %w", err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,11 @@ import (
 	"time"
 )
 
+// SchemaVersion identifies the shape of Job as stored by caches. Bump it
+// whenever a field is added, removed or changes meaning, so entries written
+// by older builds are ignored instead of decoding with missing data.
+const SchemaVersion = 2
+
 // Job represents a single job listing scraped from any source.
 type Job struct {
 	Title       string
@@ -18,6 +23,12 @@ type Job struct {
 	WorkModel   string // remoto, hibrido, presencial
 	Level       string // junior, pleno, senior
 	Salary      string // texto livre ex: "R$ 5.000 - R$ 8.000"
+
+	// Localização normalizada a partir de Location (ver internal/geo).
+	City    string // município canônico, ex: "São Paulo"
+	State   string // sigla da UF, ex: "SP"
+	Country string // ex: "Brasil"
+	Remote  bool
 }
 
 // FullText returns all searchable text fields concatenated in lowercase.
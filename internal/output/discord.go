@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 type DiscordWriter struct {
 	webhookURL string
 	client     *http.Client
+	dryRun     *previewer
 }
 
 func NewDiscordWriter(webhookURL string) *DiscordWriter {
@@ -23,20 +25,48 @@ func NewDiscordWriter(webhookURL string) *DiscordWriter {
 	}
 }
 
+// DryRun makes the writer print each message to w, exactly as it would be
+// sent, instead of calling the webhook.
+func (dw *DiscordWriter) DryRun(w io.Writer) *DiscordWriter {
+	dw.dryRun = &previewer{w: w, label: "discord (Markdown)"}
+	return dw
+}
+
 func (dw *DiscordWriter) WriteJobs(jobs []model.Job) error {
 	if len(jobs) == 0 {
 		return dw.send("Nenhuma vaga encontrada.")
 	}
 
-	// Discord has a 2000 char limit per message. Split into chunks.
-	var chunks []string
-	var current strings.Builder
 	header := fmt.Sprintf("**Encontradas %d vaga(s):**\n\n", len(jobs))
-	current.WriteString(header)
+	entries := make([]string, len(jobs))
+	for i, j := range jobs {
+		entries[i] = formatDiscordJob(i+1, j)
+	}
+	return dw.sendChunked(header, entries)
+}
 
+// WriteClosed sends a "vagas encerradas" summary for postings that were taken down.
+func (dw *DiscordWriter) WriteClosed(jobs []model.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	header := fmt.Sprintf("**Vagas encerradas (%d):**\n\n", len(jobs))
+	entries := make([]string, len(jobs))
 	for i, j := range jobs {
-		entry := formatDiscordJob(i+1, j)
+		entries[i] = formatDiscordJob(i+1, j)
+	}
+	return dw.sendChunked(header, entries)
+}
 
+// sendChunked sends header followed by entries, splitting into several
+// messages to respect Discord's 2000 char limit.
+func (dw *DiscordWriter) sendChunked(header string, entries []string) error {
+	var chunks []string
+	var current strings.Builder
+	current.WriteString(header)
+
+	for _, entry := range entries {
 		if current.Len()+len(entry) > 1900 {
 			chunks = append(chunks, current.String())
 			current.Reset()
@@ -78,6 +108,10 @@ type discordPayload struct {
 }
 
 func (dw *DiscordWriter) send(text string) error {
+	if dw.dryRun != nil {
+		return dw.dryRun.render(text)
+	}
+
 	payload, err := json.Marshal(discordPayload{Content: text})
 	if err != nil {
 		return fmt.Errorf("discord: marshaling payload: %w", err)
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+// JobJSON is the JSON representation of a job shared by the NDJSON and
+// webhook writers and the HTTP API.
+type JobJSON struct {
+	Title       string    `json:"title"`
+	Company     string    `json:"company"`
+	Location    string    `json:"location"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Source      string    `json:"source"`
+	PostedAt    time.Time `json:"posted_at,omitzero"`
+	JobType     string    `json:"tipo,omitempty"`
+	WorkModel   string    `json:"modelo,omitempty"`
+	Level       string    `json:"nivel,omitempty"`
+	Salary      string    `json:"salario,omitempty"`
+	City        string    `json:"cidade,omitempty"`
+	State       string    `json:"uf,omitempty"`
+	Country     string    `json:"pais,omitempty"`
+	Remote      bool      `json:"remoto,omitempty"`
+}
+
+// ToJSON converts a job to its JSON representation.
+func ToJSON(j model.Job) JobJSON {
+	return JobJSON{
+		Title:       j.Title,
+		Company:     j.Company,
+		Location:    j.Location,
+		URL:         j.URL,
+		Description: j.Description,
+		Source:      j.Source,
+		PostedAt:    j.PostedAt,
+		JobType:     j.JobType,
+		WorkModel:   j.WorkModel,
+		Level:       j.Level,
+		Salary:      j.Salary,
+		City:        j.City,
+		State:       j.State,
+		Country:     j.Country,
+		Remote:      j.Remote,
+	}
+}
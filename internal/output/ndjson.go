@@ -0,0 +1,39 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+// NDJSONWriter writes one JSON object per line, suitable for jq and log
+// pipelines. It supports streaming.
+type NDJSONWriter struct {
+	enc *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{enc: json.NewEncoder(w)}
+}
+
+func (nw *NDJSONWriter) WriteJobs(jobs []model.Job) error {
+	for _, j := range jobs {
+		if err := nw.WriteJob(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nw *NDJSONWriter) WriteJob(j model.Job) error {
+	if err := nw.enc.Encode(ToJSON(j)); err != nil {
+		return fmt.Errorf("ndjson: encoding job: %w", err)
+	}
+	return nil
+}
+
+func (nw *NDJSONWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// previewer renders the messages a writer would send, for dry runs.
+type previewer struct {
+	w     io.Writer
+	label string
+	sent  int
+}
+
+// render prints one message exactly as it would be sent, after chunking and
+// escaping, under a header that numbers it.
+func (p *previewer) render(text string) error {
+	p.sent++
+	_, err := fmt.Fprintf(p.w, "--- %s: mensagem %d (%d caracteres) ---\n%s\n",
+		p.label, p.sent, utf8.RuneCountInString(text), text)
+	return err
+}
@@ -3,8 +3,10 @@ package output
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
+	"github.com/rsilvagit/go-work/internal/filter"
 	"github.com/rsilvagit/go-work/internal/model"
 )
 
@@ -13,8 +15,31 @@ type ResultWriter interface {
 	WriteJobs(jobs []model.Job) error
 }
 
-// ConsolePrinter writes jobs to stdout in a formatted table.
-type ConsolePrinter struct{}
+// ClosedWriter is implemented by writers that can report postings that
+// were taken down since they were first seen.
+type ClosedWriter interface {
+	WriteClosed(jobs []model.Job) error
+}
+
+// RejectedWriter is implemented by writers that can explain why jobs were
+// dropped by the filters.
+type RejectedWriter interface {
+	WriteRejected(rejected []filter.Rejection) error
+}
+
+// StreamWriter is implemented by writers that can receive jobs one at a
+// time, as soon as they pass dedup and filters. WriteJob may be called from
+// a single goroutine only; Flush is called once when the stream ends.
+type StreamWriter interface {
+	WriteJob(j model.Job) error
+	Flush() error
+}
+
+// ConsolePrinter writes jobs to stdout in a formatted table. When streaming,
+// rows are printed as they arrive, without column alignment.
+type ConsolePrinter struct {
+	streamed int
+}
 
 func NewConsolePrinter() *ConsolePrinter {
 	return &ConsolePrinter{}
@@ -35,3 +60,66 @@ func (cp *ConsolePrinter) WriteJobs(jobs []model.Job) error {
 	}
 	return w.Flush()
 }
+
+func (cp *ConsolePrinter) WriteJob(j model.Job) error {
+	if cp.streamed == 0 {
+		fmt.Println("FONTE | TITULO | EMPRESA | LOCALIZACAO | URL")
+	}
+	cp.streamed++
+	_, err := fmt.Printf("%s | %s | %s | %s | %s\n", j.Source, j.Title, j.Company, j.Location, j.URL)
+	return err
+}
+
+func (cp *ConsolePrinter) Flush() error {
+	if cp.streamed == 0 {
+		fmt.Println("Nenhuma vaga encontrada.")
+	}
+	cp.streamed = 0
+	return nil
+}
+
+func (cp *ConsolePrinter) WriteClosed(jobs []model.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\nVagas encerradas (%d):\n", len(jobs))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FONTE\tTITULO\tEMPRESA\tURL")
+	fmt.Fprintln(w, "-----\t------\t-------\t---")
+	for _, j := range jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", j.Source, j.Title, j.Company, j.URL)
+	}
+	return w.Flush()
+}
+
+// explainMax caps the checked text shown for each dropped job.
+const explainMax = 160
+
+func (cp *ConsolePrinter) WriteRejected(rejected []filter.Rejection) error {
+	if len(rejected) == 0 {
+		fmt.Println("\nNenhuma vaga descartada pelos filtros.")
+		return nil
+	}
+
+	fmt.Printf("\nVagas descartadas pelos filtros (%d):\n", len(rejected))
+	for _, r := range rejected {
+		j := r.Job
+		fmt.Printf("\n- [%s] %s — %s\n", j.Source, j.Title, j.Company)
+		if j.URL != "" {
+			fmt.Printf("  %s\n", j.URL)
+		}
+		fmt.Printf("  regra: %s (exige %q)\n", r.Rule, r.Criterion)
+		fmt.Printf("  verificado: %s\n", excerpt(r.Checked, explainMax))
+	}
+	return nil
+}
+
+// excerpt collapses whitespace and cuts s to at most limit runes.
+func excerpt(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > limit {
+		return string(r[:limit]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rsilvagit/go-work/internal/report"
+)
+
+// SummaryWriter is implemented by writers that can present the run summary.
+type SummaryWriter interface {
+	WriteSummary(s report.Summary) error
+}
+
+func (cp *ConsolePrinter) WriteSummary(s report.Summary) error {
+	var b strings.Builder
+	if err := s.WriteText(&b); err != nil {
+		return err
+	}
+	_, err := fmt.Printf("\n%s", b.String())
+	return err
+}
+
+// WriteSummary sends the summary as monospaced blocks.
+func (tw *TelegramWriter) WriteSummary(s report.Summary) error {
+	var b strings.Builder
+	if err := s.WriteText(&b); err != nil {
+		return err
+	}
+
+	// Dentro de blocos pre do MarkdownV2 só ` e \ precisam de escape.
+	escaper := strings.NewReplacer("\\", "\\\\", "`", "\\`")
+	for _, chunk := range chunkLines(b.String(), 3800) {
+		if err := tw.send("```\n" + escaper.Replace(chunk) + "```"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSummary sends the summary as code blocks.
+func (dw *DiscordWriter) WriteSummary(s report.Summary) error {
+	var b strings.Builder
+	if err := s.WriteText(&b); err != nil {
+		return err
+	}
+
+	for _, chunk := range chunkLines(strings.ReplaceAll(b.String(), "```", "'''"), 1900) {
+		if err := dw.send("```\n" + chunk + "```"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkLines splits text on line boundaries into pieces of at most limit
+// bytes. A single line longer than limit is cut.
+func chunkLines(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len(line) > limit {
+			chunks = append(chunks, line[:limit])
+			line = line[limit:]
+		}
+		if current.Len()+len(line) > limit {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -15,6 +16,7 @@ type TelegramWriter struct {
 	token  string
 	chatID string
 	client *http.Client
+	dryRun *previewer
 }
 
 func NewTelegramWriter(token, chatID string) *TelegramWriter {
@@ -25,20 +27,53 @@ func NewTelegramWriter(token, chatID string) *TelegramWriter {
 	}
 }
 
+// ChatID returns the chat the writer sends to.
+func (tw *TelegramWriter) ChatID() string {
+	return tw.chatID
+}
+
+// DryRun makes the writer print each message to w, exactly as it would be
+// sent, instead of calling the Bot API.
+func (tw *TelegramWriter) DryRun(w io.Writer) *TelegramWriter {
+	tw.dryRun = &previewer{w: w, label: "telegram (MarkdownV2, chat " + tw.chatID + ")"}
+	return tw
+}
+
 func (tw *TelegramWriter) WriteJobs(jobs []model.Job) error {
 	if len(jobs) == 0 {
 		return tw.send("Nenhuma vaga encontrada.")
 	}
 
-	// Telegram has a 4096 char limit per message. Split into chunks.
-	var chunks []string
-	var current strings.Builder
 	header := fmt.Sprintf("*Encontradas %d vaga(s):*\n\n", len(jobs))
-	current.WriteString(header)
+	entries := make([]string, len(jobs))
+	for i, j := range jobs {
+		entries[i] = formatJob(i+1, j)
+	}
+	return tw.sendChunked(header, entries)
+}
 
+// WriteClosed sends a "vagas encerradas" summary for postings that were taken down.
+func (tw *TelegramWriter) WriteClosed(jobs []model.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	header := fmt.Sprintf("*Vagas encerradas \\(%d\\):*\n\n", len(jobs))
+	entries := make([]string, len(jobs))
 	for i, j := range jobs {
-		entry := formatJob(i+1, j)
+		entries[i] = formatJob(i+1, j)
+	}
+	return tw.sendChunked(header, entries)
+}
+
+// sendChunked sends header followed by entries, splitting into several
+// messages to respect Telegram's 4096 char limit.
+func (tw *TelegramWriter) sendChunked(header string, entries []string) error {
+	var chunks []string
+	var current strings.Builder
+	current.WriteString(header)
 
+	for _, entry := range entries {
 		if current.Len()+len(entry) > 3800 {
 			chunks = append(chunks, current.String())
 			current.Reset()
@@ -82,6 +117,10 @@ func escapeMarkdown(s string) string {
 }
 
 func (tw *TelegramWriter) send(text string) error {
+	if tw.dryRun != nil {
+		return tw.dryRun.render(text)
+	}
+
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", tw.token)
 
 	payload := map[string]string{
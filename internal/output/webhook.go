@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+// WebhookWriter POSTs jobs as JSON to a generic HTTP endpoint. In streaming
+// mode each job is posted as soon as it passes the filters; in batch mode
+// the whole list is posted once.
+type WebhookWriter struct {
+	url    string
+	client *http.Client
+	dryRun *previewer
+}
+
+func NewWebhookWriter(url string) *WebhookWriter {
+	return &WebhookWriter{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// DryRun makes the writer print each JSON payload to w instead of posting it.
+func (ww *WebhookWriter) DryRun(w io.Writer) *WebhookWriter {
+	ww.dryRun = &previewer{w: w, label: "webhook (JSON)"}
+	return ww
+}
+
+type webhookBatch struct {
+	Total int       `json:"total"`
+	Jobs  []JobJSON `json:"jobs"`
+}
+
+func (ww *WebhookWriter) WriteJobs(jobs []model.Job) error {
+	batch := webhookBatch{Total: len(jobs), Jobs: make([]JobJSON, 0, len(jobs))}
+	for _, j := range jobs {
+		batch.Jobs = append(batch.Jobs, ToJSON(j))
+	}
+	return ww.post(batch)
+}
+
+func (ww *WebhookWriter) WriteJob(j model.Job) error {
+	return ww.post(ToJSON(j))
+}
+
+func (ww *WebhookWriter) Flush() error {
+	return nil
+}
+
+func (ww *WebhookWriter) post(v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("webhook: marshaling payload: %w", err)
+	}
+	if ww.dryRun != nil {
+		return ww.dryRun.render(string(payload))
+	}
+
+	resp, err := ww.client.Post(ww.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("webhook: sending: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/metrics"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/report"
+)
+
+// notify sends the outcome of a search to every configured writer and
+// returns the run summary completed with the writer failures.
+func (e *Engine) notify(req SearchRequest, out Result) report.Summary {
+	summary := out.Summary
+	summary.WriterErrors = make(map[string]error)
+	writerFailed := func(w output.ResultWriter, err error) {
+		metrics.WriterSends.WithLabelValues(writerName(w), metrics.Result(err)).Inc()
+		if err != nil {
+			e.opts.Logger.Error("falha ao enviar para o canal", "writer", writerName(w), "err", err)
+			summary.WriterErrors[writerName(w)] = err
+		}
+	}
+
+	for _, w := range e.opts.Writers {
+		// Com -stream, os StreamWriters já receberam as vagas.
+		if _, ok := w.(output.StreamWriter); ok && out.Streamed {
+			writerFailed(w, out.StreamErrors[writerName(w)])
+			continue
+		}
+		writerFailed(w, w.WriteJobs(out.Jobs))
+	}
+
+	if len(out.Closed) > 0 {
+		// O console sempre mostra as encerradas; os demais canais só com -notify-closed.
+		for _, w := range e.opts.Writers {
+			cw, ok := w.(output.ClosedWriter)
+			if _, console := w.(*output.ConsolePrinter); !ok || (!console && !req.NotifyClosed) {
+				continue
+			}
+			writerFailed(w, cw.WriteClosed(out.Closed))
+		}
+	}
+
+	// Os motivos dos descartes vão só para o console, com -explain.
+	if req.Explain {
+		for _, w := range e.opts.Writers {
+			if rw, ok := w.(output.RejectedWriter); ok {
+				writerFailed(w, rw.WriteRejected(out.Rejected))
+			}
+		}
+	}
+
+	e.opts.Logger.Info("execução concluída", "status", summary.Status(),
+		"jobs", summary.Matched, "failed_searches", summary.Failed(), "duration", summary.Duration.Round(time.Millisecond))
+
+	// O console mostra o resumo fora do modo silencioso; os demais canais
+	// só com -notify-summary.
+	for _, w := range e.opts.Writers {
+		sw, ok := w.(output.SummaryWriter)
+		if !ok {
+			continue
+		}
+		_, console := w.(*output.ConsolePrinter)
+		if (console && e.opts.Quiet) || (!console && !req.NotifySummary) {
+			continue
+		}
+		if err := sw.WriteSummary(summary); err != nil {
+			e.opts.Logger.Error("falha ao enviar resumo", "writer", writerName(w), "err", err)
+		}
+	}
+	return summary
+}
+
+// writerName returns the metrics label for a writer, also its key in
+// Summary.WriterErrors. Telegram writers carry their chat, since one run may
+// send to several chats.
+func writerName(w output.ResultWriter) string {
+	switch w := w.(type) {
+	case *output.ConsolePrinter:
+		return "console"
+	case *output.TelegramWriter:
+		return "telegram:" + w.ChatID()
+	case *output.DiscordWriter:
+		return "discord"
+	case *output.NDJSONWriter:
+		return "ndjson"
+	case *output.WebhookWriter:
+		return "webhook"
+	default:
+		return fmt.Sprintf("%T", w)
+	}
+}
@@ -0,0 +1,422 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/cache"
+	"github.com/rsilvagit/go-work/internal/dispatch"
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/history"
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/report"
+	"github.com/rsilvagit/go-work/internal/scraper"
+)
+
+// ErrAllFailed is returned by Run when every search of the request failed.
+var ErrAllFailed = errors.New("pipeline: every search failed")
+
+// DedupFunc returns the identity of a job: jobs with the same key are
+// duplicates and only the first one is kept.
+type DedupFunc func(model.Job) string
+
+// History records the searches and finds the jobs that left them, keyed
+// by the Engine's DedupFunc. *history.Store implements it.
+type History interface {
+	RecordSearch(ctx context.Context, key func(model.Job) string, source, query, location string, jobs []model.Job, at time.Time) error
+	Missing(ctx context.Context, key func(model.Job) string, source, query, location string, present []model.Job) ([]history.Record, error)
+	MarkClosed(ctx context.Context, key func(model.Job) string, jobs []model.Job, at time.Time) error
+}
+
+// Options holds the dependencies and limits of an Engine. Cache, History
+// and Client are optional.
+type Options struct {
+	Scrapers []scraper.Scraper
+	Cache    cache.Cache
+	History  History
+	// Client confirms closed jobs with the site (see history.ConfirmClosed);
+	// without it every missing job counts as closed.
+	Client  history.Doer
+	Writers []output.ResultWriter
+	Dedup   DedupFunc // default model.Job.Key (URL, or title+company)
+
+	Timeout      time.Duration // deadline of each scraper (default 30s)
+	QueryTimeout time.Duration // deadline of each query; 0 = scraper's only
+	Concurrency  int           // searches running at once, in total
+	PerSource    int           // searches running at once per source
+
+	// ConfirmTimeout bounds the URL checks of the closed jobs of a run
+	// (default 30s); ConfirmConcurrency is how many run at once (default 8).
+	ConfirmTimeout     time.Duration
+	ConfirmConcurrency int
+
+	// Background serves stale cache entries at once and refreshes them in
+	// the background; for long-lived processes, which outlive the refresh.
+	Background bool
+	// Quiet hides the summary on the console.
+	Quiet  bool
+	Logger *slog.Logger
+}
+
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.ConfirmTimeout <= 0 {
+		o.ConfirmTimeout = 30 * time.Second
+	}
+	if o.ConfirmConcurrency <= 0 {
+		o.ConfirmConcurrency = 8
+	}
+	if o.Dedup == nil {
+		o.Dedup = model.Job.Key
+	}
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+	return o
+}
+
+// SearchRequest describes one search run.
+type SearchRequest struct {
+	Queries       []string
+	Location      string
+	Filter        filter.Options
+	DetectClosed  bool
+	NotifyClosed  bool
+	NotifySummary bool
+	// Stream envia as vagas aos StreamWriters assim que passam pelos filtros.
+	Stream bool
+	// Notify sends the result to the writers when the run ends.
+	Notify bool
+	// Explain keeps, for each job dropped by the filters, the rule that
+	// rejected it; the console shows them when the result is notified.
+	Explain bool
+}
+
+// Result is what a search run produced, after dedup and filters.
+type Result struct {
+	Jobs    []model.Job
+	Closed  []model.Job
+	Summary report.Summary
+	// Rejected explains the jobs dropped by the filters, with req.Explain.
+	Rejected []filter.Rejection
+	// Streamed reports whether the stream writers already received Jobs;
+	// StreamErrors maps their names to the first error they returned.
+	Streamed     bool
+	StreamErrors map[string]error
+}
+
+// Engine runs searches: fan-out to the scrapers, cache, dedup, history,
+// filters and writers. It is safe for concurrent use.
+type Engine struct {
+	opts Options
+
+	refreshing sync.Map // buscas sendo atualizadas
+	refreshWG  sync.WaitGroup
+}
+
+// New creates an Engine.
+func New(opts Options) *Engine {
+	return &Engine{opts: opts.withDefaults()}
+}
+
+// Sources lists the names of the scrapers.
+func (e *Engine) Sources() []string {
+	names := make([]string, len(e.opts.Scrapers))
+	for i, s := range e.opts.Scrapers {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// Close waits for the background cache refreshes. The injected
+// dependencies are left to the caller.
+func (e *Engine) Close() {
+	e.refreshWG.Wait()
+}
+
+// Run searches, and with req.Notify sends the result to the writers. The
+// result is returned even with ErrAllFailed, so its summary can be reported.
+func (e *Engine) Run(ctx context.Context, req SearchRequest) (Result, error) {
+	res := e.search(ctx, req)
+	if req.Notify {
+		res.Summary = e.notify(req, res)
+	}
+	if res.Summary.AllFailed() {
+		return res, fmt.Errorf("%w: %w", ErrAllFailed, res.Summary.Searches[0].Err)
+	}
+	return res, nil
+}
+
+// search fans out every query to every scraper, deduplicates the results,
+// records them in the history and applies the filters. Each scraper gets
+// its own deadline (and each query its own, with QueryTimeout), so a slow
+// source never starves the others; jobs fetched before a deadline are kept.
+// With req.Stream, jobs also flow to the stream writers while the searches run.
+func (e *Engine) search(ctx context.Context, req SearchRequest) Result {
+	started := time.Now()
+	log := e.opts.Logger
+
+	var (
+		emit       chan<- model.Job
+		streamDone <-chan map[string]error
+	)
+	if req.Stream {
+		emit, streamDone = e.stream(req.Filter)
+	}
+
+	var (
+		mu       sync.Mutex
+		allJobs  []model.Job
+		searches []searchResult
+		stats    []report.Search
+		tasks    []dispatch.Task
+	)
+
+	loc := req.Location
+	for _, s := range e.opts.Scrapers {
+		// O prazo do scraper começa na sua primeira busca, não na fila.
+		deadline := &scraperDeadline{parent: ctx, timeout: e.opts.Timeout}
+		defer deadline.cancel()
+
+		for i, term := range req.Queries {
+			tasks = append(tasks, dispatch.Task{
+				Source:   s.Name(),
+				Priority: i, // termos na ordem em que foram informados
+				Run: func(context.Context) {
+					ctx := deadline.context()
+					if e.opts.QueryTimeout > 0 {
+						var cancel context.CancelFunc
+						ctx, cancel = context.WithTimeout(ctx, e.opts.QueryTimeout)
+						defer cancel()
+					}
+
+					jobs, stat := e.searchOne(ctx, s, term, loc, emit)
+					mu.Lock()
+					defer mu.Unlock()
+					stats = append(stats, stat)
+					if stat.Err == nil || stat.Partial() {
+						allJobs = append(allJobs, jobs...)
+						searches = append(searches, searchResult{s.Name(), term, jobs, stat.Err != nil})
+					}
+				},
+			})
+		}
+	}
+
+	skipped := dispatch.Run(ctx, tasks, dispatch.Options{
+		Concurrency: e.opts.Concurrency,
+		PerSource:   e.opts.PerSource,
+		Progress: func(t dispatch.Task, done, total int) {
+			log.Info("progresso", "scraper", t.Source, "done", done, "total", total)
+		},
+	})
+	// Buscas que ficaram na fila quando ctx acabou; Priority é o índice do termo.
+	for _, t := range skipped {
+		stats = append(stats, report.Search{Scraper: t.Source, Query: req.Queries[t.Priority], Err: ctx.Err()})
+	}
+
+	var streamErrors map[string]error
+	if emit != nil {
+		close(emit)
+		streamErrors = <-streamDone
+	}
+
+	// Deduplicate jobs by the dedup key.
+	seen := make(map[string]bool)
+	var uniqueJobs []model.Job
+	for _, j := range allJobs {
+		key := e.opts.Dedup(j)
+		if !seen[key] {
+			seen[key] = true
+			uniqueJobs = append(uniqueJobs, j)
+		}
+	}
+
+	// Registrar todas as vagas vistas, antes dos filtros, e detectar as
+	// que sumiram das buscas desde a última execução.
+	var closedJobs []model.Job
+	if e.opts.History != nil {
+		closedJobs = e.record(ctx, req, searches, seen)
+	} else if req.DetectClosed {
+		log.Warn("-detect-closed requer -history-db, ignorando")
+	}
+
+	summary := report.Summary{
+		Searches:  stats,
+		Collected: len(allJobs),
+		Unique:    len(uniqueJobs),
+		Closed:    len(closedJobs),
+	}
+
+	// Apply filters.
+	var rejected []filter.Rejection
+	if req.Explain {
+		uniqueJobs, summary.Dropped, rejected = filter.ApplyExplain(uniqueJobs, req.Filter)
+	} else {
+		uniqueJobs, summary.Dropped = filter.ApplyWithStats(uniqueJobs, req.Filter)
+	}
+	summary.Matched = len(uniqueJobs)
+	summary.Duration = time.Since(started)
+
+	return Result{
+		Jobs:         uniqueJobs,
+		Closed:       closedJobs,
+		Summary:      summary,
+		Rejected:     rejected,
+		Streamed:     req.Stream,
+		StreamErrors: streamErrors,
+	}
+}
+
+// searchResult is a finished search, kept for the history.
+type searchResult struct {
+	source  string
+	query   string
+	jobs    []model.Job
+	partial bool
+}
+
+// record saves each search in the history and, with req.DetectClosed,
+// returns the jobs that left them and are confirmed closed. present holds
+// the dedup keys of every job of the run: a job still listed by another
+// search is not closed. Each search writes under its own deadline, and the
+// URL checks of all of them share ConfirmTimeout.
+func (e *Engine) record(ctx context.Context, req SearchRequest, searches []searchResult, present map[string]bool) []model.Job {
+	h, log := e.opts.History, e.opts.Logger
+	ctx = context.WithoutCancel(ctx) // o histórico é gravado mesmo com a busca cancelada
+	now := time.Now()
+
+	var (
+		candidates []history.Record
+		seen       = make(map[string]bool)
+	)
+	for _, sr := range searches {
+		sctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+		// Um resultado parcial não prova que as vagas ausentes sumiram.
+		if req.DetectClosed && !sr.partial {
+			missing, err := h.Missing(sctx, e.opts.Dedup, sr.source, sr.query, req.Location, sr.jobs)
+			if err != nil {
+				log.Warn("falha ao consultar histórico", "scraper", sr.source, "query", sr.query, "err", err)
+			}
+			for _, r := range missing {
+				key := e.opts.Dedup(r.Job)
+				if !present[key] && !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, r)
+				}
+			}
+		}
+		if err := h.RecordSearch(sctx, e.opts.Dedup, sr.source, sr.query, req.Location, sr.jobs, now); err != nil {
+			log.Warn("falha ao gravar histórico", "scraper", sr.source, "query", sr.query, "err", err)
+		}
+		cancel()
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, e.opts.ConfirmTimeout)
+	closed := e.confirmClosed(cctx, candidates)
+	cancel()
+	if len(closed) == 0 {
+		return nil
+	}
+
+	mctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+	defer cancel()
+	if err := h.MarkClosed(mctx, e.opts.Dedup, closed, now); err != nil {
+		log.Warn("falha ao marcar vagas encerradas", "err", err)
+	}
+	return closed
+}
+
+// confirmClosed keeps the missing jobs whose pages are gone, or all of them
+// without a Client.
+func (e *Engine) confirmClosed(ctx context.Context, missing []history.Record) []model.Job {
+	if e.opts.Client != nil {
+		return history.ConfirmClosed(ctx, e.opts.Client, missing, e.opts.ConfirmConcurrency)
+	}
+	jobs := make([]model.Job, len(missing))
+	for i, r := range missing {
+		jobs[i] = r.Job
+	}
+	return jobs
+}
+
+// stream starts the incremental pipeline: jobs sent on the returned channel
+// are deduplicated and filtered one by one and handed to every StreamWriter.
+// Once the channel is closed the writers are flushed and their errors are
+// delivered on done. A writer that fails stops receiving jobs.
+func (e *Engine) stream(opts filter.Options) (chan<- model.Job, <-chan map[string]error) {
+	in := make(chan model.Job, 64)
+	done := make(chan map[string]error, 1)
+
+	var writers []output.StreamWriter
+	for _, w := range e.opts.Writers {
+		if sw, ok := w.(output.StreamWriter); ok {
+			writers = append(writers, sw)
+		}
+	}
+
+	go func() {
+		errs := make(map[string]error)
+		seen := make(map[string]bool)
+		for j := range in {
+			key := e.opts.Dedup(j)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if !filter.Match(j, opts) {
+				continue
+			}
+			for _, sw := range writers {
+				name := writerName(sw.(output.ResultWriter))
+				if errs[name] != nil {
+					continue
+				}
+				errs[name] = sw.WriteJob(j)
+			}
+		}
+		for _, sw := range writers {
+			name := writerName(sw.(output.ResultWriter))
+			if errs[name] == nil {
+				errs[name] = sw.Flush()
+			}
+		}
+		done <- errs
+	}()
+	return in, done
+}
+
+// scraperDeadline is a per-scraper context whose timeout starts when the
+// scraper's first search starts, so time spent queued doesn't count.
+type scraperDeadline struct {
+	parent  context.Context
+	timeout time.Duration
+
+	once sync.Once
+	ctx  context.Context
+	stop context.CancelFunc
+}
+
+func (d *scraperDeadline) context() context.Context {
+	d.once.Do(func() {
+		d.ctx, d.stop = context.WithTimeout(d.parent, d.timeout)
+	})
+	return d.ctx
+}
+
+func (d *scraperDeadline) cancel() {
+	d.once.Do(func() {})
+	if d.stop != nil {
+		d.stop()
+	}
+}
@@ -0,0 +1,444 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/cache"
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/history"
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/report"
+	"github.com/rsilvagit/go-work/internal/scraper"
+	"github.com/rsilvagit/go-work/internal/store"
+)
+
+var errSource = errors.New("fonte fora do ar")
+
+// fakeScraper returns fixed jobs and error, counting its calls.
+type fakeScraper struct {
+	name  string
+	jobs  []model.Job
+	err   error
+	calls atomic.Int32
+}
+
+func (s *fakeScraper) Name() string { return s.name }
+
+func (s *fakeScraper) Search(ctx context.Context, query, location string) ([]model.Job, error) {
+	s.calls.Add(1)
+	return slices.Clone(s.jobs), s.err
+}
+
+// streamWriter records the jobs it receives, one by one or in batch.
+type streamWriter struct {
+	mu      sync.Mutex
+	stream  []string
+	batch   []string
+	flushed bool
+}
+
+func (w *streamWriter) WriteJobs(jobs []model.Job) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batch = titles(jobs)
+	return nil
+}
+
+func (w *streamWriter) WriteJob(j model.Job) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stream = append(w.stream, j.Title)
+	return nil
+}
+
+func (w *streamWriter) Flush() error {
+	w.flushed = true
+	return nil
+}
+
+func job(title, url string) model.Job {
+	return model.Job{Title: title, Company: "ACME", URL: url, Location: "São Paulo, SP", PostedAt: time.Now()}
+}
+
+func titles(jobs []model.Job) []string {
+	out := make([]string, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.Title
+	}
+	slices.Sort(out)
+	return out
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newCache(t *testing.T, opts cache.Options) cache.Cache {
+	t.Helper()
+	c := cache.New(store.NewMemory(store.DefaultMemoryBytes, 100), opts)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		scrapers []*fakeScraper
+		// setup adjusts the options, e.g. adding a cache.
+		setup     func(t *testing.T, opts *Options)
+		req       SearchRequest
+		want      []string
+		wantCalls []int32 // chamadas esperadas a cada scraper
+		wantExit  int
+		wantErr   error
+		check     func(t *testing.T, res Result)
+	}{
+		{
+			name: "default dedup by URL",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{job("Go Dev", "https://a/1"), job("Go Dev", "https://a/1")}},
+				{name: "b", jobs: []model.Job{job("Go Dev", "https://b/1")}},
+			},
+			want:      []string{"Go Dev", "Go Dev"},
+			wantCalls: []int32{1, 1},
+			wantExit:  report.ExitOK,
+		},
+		{
+			name: "dedup through Options.Dedup",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{job("Go Dev", "https://a/1"), job("Rust Dev", "https://a/2")}},
+				{name: "b", jobs: []model.Job{job("Go Dev", "https://b/1")}},
+			},
+			setup: func(t *testing.T, opts *Options) {
+				opts.Dedup = func(j model.Job) string { return strings.ToLower(j.Title) }
+			},
+			want:      []string{"Go Dev", "Rust Dev"},
+			wantCalls: []int32{1, 1},
+			wantExit:  report.ExitOK,
+			check: func(t *testing.T, res Result) {
+				if res.Summary.Collected != 3 || res.Summary.Unique != 2 {
+					t.Errorf("collected/unique = %d/%d, want 3/2", res.Summary.Collected, res.Summary.Unique)
+				}
+			},
+		},
+		{
+			name: "partial results are kept",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{job("Go Dev", "https://a/1")}, err: context.DeadlineExceeded},
+			},
+			want:      []string{"Go Dev"},
+			wantCalls: []int32{1},
+			wantExit:  report.ExitPartialFailure,
+			check: func(t *testing.T, res Result) {
+				if !res.Summary.Searches[0].Partial() {
+					t.Error("search not reported as partial")
+				}
+			},
+		},
+		{
+			name: "stale cache entry served when the source fails",
+			scrapers: []*fakeScraper{
+				{name: "a", err: errSource},
+			},
+			setup: func(t *testing.T, opts *Options) {
+				c := newCache(t, cache.Options{TTL: time.Nanosecond})
+				c.Set(ctx, "a", "golang", "", []model.Job{job("Cached Dev", "https://a/9")})
+				time.Sleep(time.Millisecond)
+				opts.Cache = c
+			},
+			want:      []string{"Cached Dev"},
+			wantCalls: []int32{1},
+			wantExit:  report.ExitOK,
+			check: func(t *testing.T, res Result) {
+				if s := res.Summary.Searches[0]; !s.CacheHit || !s.Stale || s.Err != nil {
+					t.Errorf("search = %+v, want a stale cache hit without error", s)
+				}
+			},
+		},
+		{
+			name: "fresh cache entry skips the source",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{job("Live Dev", "https://a/1")}},
+			},
+			setup: func(t *testing.T, opts *Options) {
+				c := newCache(t, cache.Options{})
+				c.Set(ctx, "a", "golang", "", []model.Job{job("Cached Dev", "https://a/9")})
+				opts.Cache = c
+			},
+			want:      []string{"Cached Dev"},
+			wantCalls: []int32{0},
+			wantExit:  report.ExitOK,
+		},
+		{
+			name: "negative cache hit skips the source",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{job("Live Dev", "https://a/1")}},
+				{name: "b", jobs: []model.Job{job("Other Dev", "https://b/1")}},
+			},
+			setup: func(t *testing.T, opts *Options) {
+				c := newCache(t, cache.Options{})
+				c.SetError(ctx, "a", "golang", "", errSource)
+				opts.Cache = c
+			},
+			want:      []string{"Other Dev"},
+			wantCalls: []int32{0, 1},
+			wantExit:  report.ExitPartialFailure,
+			check: func(t *testing.T, res Result) {
+				for _, s := range res.Summary.Searches {
+					if s.Scraper == "a" && (!s.CacheHit || s.Err == nil) {
+						t.Errorf("search = %+v, want a cached failure", s)
+					}
+				}
+			},
+		},
+		{
+			name: "every source failing",
+			scrapers: []*fakeScraper{
+				{name: "a", err: errSource},
+				{name: "b", err: errSource},
+			},
+			wantCalls: []int32{1, 1},
+			wantExit:  report.ExitAllSourcesFailed,
+			wantErr:   ErrAllFailed,
+		},
+		{
+			name: "filters drop jobs after dedup",
+			scrapers: []*fakeScraper{
+				{name: "a", jobs: []model.Job{
+					job("Go Dev", "https://a/1"),
+					{Title: "Old Dev", URL: "https://a/2", PostedAt: time.Now().Add(-48 * time.Hour)},
+					{Title: "Rio Dev", URL: "https://a/3", Location: "Rio de Janeiro, RJ", PostedAt: time.Now()},
+				}},
+			},
+			req:       SearchRequest{Filter: filter.Options{State: "SP"}, Explain: true},
+			want:      []string{"Go Dev"},
+			wantCalls: []int32{1},
+			wantExit:  report.ExitOK,
+			check: func(t *testing.T, res Result) {
+				if got := res.Summary.Dropped; got[filter.RuleMaxAge] != 1 || got[filter.RuleState] != 1 {
+					t.Errorf("dropped = %v, want one by max_age and one by uf", got)
+				}
+				if len(res.Rejected) != 2 {
+					t.Errorf("%d rejections, want 2", len(res.Rejected))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Logger: quietLogger()}
+			for _, s := range tt.scrapers {
+				opts.Scrapers = append(opts.Scrapers, s)
+			}
+			if tt.setup != nil {
+				tt.setup(t, &opts)
+			}
+			req := tt.req
+			req.Queries = []string{"golang"}
+
+			e := New(opts)
+			defer e.Close()
+			res, err := e.Run(ctx, req)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if got := titles(res.Jobs); !slices.Equal(got, tt.want) {
+				t.Errorf("jobs = %v, want %v", got, tt.want)
+			}
+			if got := res.Summary.ExitCode(); got != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", got, tt.wantExit)
+			}
+			for i, s := range tt.scrapers {
+				if got := s.calls.Load(); got != tt.wantCalls[i] {
+					t.Errorf("scraper %s called %d times, want %d", s.name, got, tt.wantCalls[i])
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, res)
+			}
+		})
+	}
+}
+
+func TestRunCachesFailures(t *testing.T) {
+	ctx := context.Background()
+	s := &fakeScraper{name: "a", err: errSource}
+	e := New(Options{
+		Scrapers: []scraper.Scraper{s},
+		Cache:    newCache(t, cache.Options{}),
+		Logger:   quietLogger(),
+	})
+	defer e.Close()
+
+	req := SearchRequest{Queries: []string{"golang"}}
+	for range 2 {
+		if _, err := e.Run(ctx, req); !errors.Is(err, ErrAllFailed) {
+			t.Fatalf("err = %v, want ErrAllFailed", err)
+		}
+	}
+	if got := s.calls.Load(); got != 1 {
+		t.Errorf("scraper called %d times, want 1 (the failure is cached)", got)
+	}
+}
+
+// Com o contexto encerrado, as buscas na fila não começam, mas entram no
+// resumo como falhas.
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a, b := &fakeScraper{name: "a"}, &fakeScraper{name: "b"}
+	e := New(Options{Scrapers: []scraper.Scraper{a, b}, Logger: quietLogger()})
+	defer e.Close()
+
+	res, err := e.Run(ctx, SearchRequest{Queries: []string{"golang", "rust"}})
+	if !errors.Is(err, ErrAllFailed) || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want ErrAllFailed with context.Canceled", err)
+	}
+	if a.calls.Load()+b.calls.Load() != 0 {
+		t.Errorf("scrapers called %d and %d times, want 0", a.calls.Load(), b.calls.Load())
+	}
+	var got []string
+	for _, s := range res.Summary.Searches {
+		got = append(got, s.Scraper+"/"+s.Query)
+	}
+	slices.Sort(got)
+	if want := []string{"a/golang", "a/rust", "b/golang", "b/rust"}; !slices.Equal(got, want) {
+		t.Errorf("summary searches = %v, want %v", got, want)
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	remote := func(title, url string) model.Job {
+		j := job(title, url)
+		j.WorkModel = "remoto"
+		return j
+	}
+	w := &streamWriter{}
+	e := New(Options{
+		Scrapers: []scraper.Scraper{
+			&fakeScraper{name: "a", jobs: []model.Job{remote("Go Dev", "https://a/1"), job("Office Dev", "https://a/2")}},
+			&fakeScraper{name: "b", jobs: []model.Job{remote("GO DEV", "https://b/1"), remote("Rust Dev", "https://b/2")}},
+		},
+		Writers: []output.ResultWriter{w},
+		Dedup:   func(j model.Job) string { return strings.ToLower(j.Title) },
+		Logger:  quietLogger(),
+	})
+	defer e.Close()
+
+	res, err := e.Run(context.Background(), SearchRequest{
+		Queries: []string{"golang"},
+		Filter:  filter.Options{WorkModel: "remoto"},
+		Stream:  true,
+		Notify:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(w.stream)
+	if len(w.stream) != 2 || w.stream[1] != "Rust Dev" || !strings.EqualFold(w.stream[0], "go dev") {
+		t.Errorf("streamed %v, want one Go Dev and Rust Dev", w.stream)
+	}
+	if !w.flushed {
+		t.Error("stream writer not flushed")
+	}
+	if w.batch != nil {
+		t.Errorf("stream writer also got the batch %v", w.batch)
+	}
+	if !res.Streamed || len(res.Jobs) != 2 {
+		t.Errorf("streamed=%v jobs=%d, want true and 2", res.Streamed, len(res.Jobs))
+	}
+}
+
+// fakeHistory returns fixed missing records and remembers what was
+// recorded and closed, by the key it was given.
+type fakeHistory struct {
+	missing  map[string][]history.Record // por termo
+	recorded []string
+	closed   []string
+}
+
+func (h *fakeHistory) RecordSearch(ctx context.Context, key func(model.Job) string, source, query, location string, jobs []model.Job, at time.Time) error {
+	for _, j := range jobs {
+		h.recorded = append(h.recorded, source+"/"+query+"/"+key(j))
+	}
+	return nil
+}
+
+func (h *fakeHistory) Missing(ctx context.Context, key func(model.Job) string, source, query, location string, present []model.Job) ([]history.Record, error) {
+	return h.missing[query], nil
+}
+
+func (h *fakeHistory) MarkClosed(ctx context.Context, key func(model.Job) string, jobs []model.Job, at time.Time) error {
+	for _, j := range jobs {
+		h.closed = append(h.closed, key(j))
+	}
+	return nil
+}
+
+func TestRunDetectClosed(t *testing.T) {
+	gone := history.Record{Job: job("Gone Dev", "")}
+	h := &fakeHistory{missing: map[string][]history.Record{
+		// A mesma vaga some dos dois termos; a outra ainda aparece em "rust".
+		"golang": {gone, {Job: job("Rust Dev", "")}},
+		"go":     {gone},
+	}}
+	e := New(Options{
+		Scrapers: []scraper.Scraper{&fakeScraper{name: "a", jobs: []model.Job{job("Rust Dev", "")}}},
+		History:  h,
+		// O histórico usa a mesma chave da deduplicação.
+		Dedup:  func(j model.Job) string { return "id:" + strings.ToLower(j.Title) },
+		Logger: quietLogger(),
+	})
+	defer e.Close()
+
+	res, err := e.Run(context.Background(), SearchRequest{Queries: []string{"golang", "go"}, DetectClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(res.Closed); !slices.Equal(got, []string{"Gone Dev"}) {
+		t.Errorf("closed = %v, want [Gone Dev]", got)
+	}
+	if !slices.Equal(h.closed, []string{"id:gone dev"}) {
+		t.Errorf("marked closed %v, want [id:gone dev]", h.closed)
+	}
+	slices.Sort(h.recorded)
+	if want := []string{"a/go/id:rust dev", "a/golang/id:rust dev"}; !slices.Equal(h.recorded, want) {
+		t.Errorf("recorded %v, want %v", h.recorded, want)
+	}
+}
+
+func TestWriterName(t *testing.T) {
+	tests := []struct {
+		w    output.ResultWriter
+		want string
+	}{
+		{output.NewConsolePrinter(), "console"},
+		{output.NewTelegramWriter("token", "-100123"), "telegram:-100123"},
+		{output.NewTelegramWriter("token", "@canal"), "telegram:@canal"},
+		{&streamWriter{}, "*pipeline.streamWriter"},
+	}
+	for _, tt := range tests {
+		if got := writerName(tt.w); got != tt.want {
+			t.Errorf("writerName(%T) = %q, want %q", tt.w, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/cache"
+	"github.com/rsilvagit/go-work/internal/geo"
+	"github.com/rsilvagit/go-work/internal/metrics"
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/report"
+	"github.com/rsilvagit/go-work/internal/scraper"
+)
+
+// searchOne runs one scraper×query search, consulting and filling the cache.
+// On failure the jobs fetched before the error are returned (partial result),
+// or else a stale cache entry. When emit is not nil, every job is also sent on
+// it as soon as it is known.
+func (e *Engine) searchOne(ctx context.Context, s scraper.Scraper, term, loc string, emit chan<- model.Job) ([]model.Job, report.Search) {
+	log := e.opts.Logger.With("scraper", s.Name(), "query", term)
+
+	// Verificar cache primeiro.
+	var stale *cache.Entry
+	if e.opts.Cache != nil {
+		entry, ok := e.opts.Cache.Lookup(ctx, s.Name(), term, loc)
+		switch {
+		case !ok || (entry.Stale() && entry.Negative()):
+			metrics.CacheRequests.WithLabelValues(s.Name(), "miss").Inc()
+		case entry.Err != "":
+			metrics.CacheRequests.WithLabelValues(s.Name(), "negative").Inc()
+			log.Info("falha recente em cache, busca ignorada", "err", entry.Err, "age", entry.Age().Round(time.Second))
+			return nil, report.Search{Scraper: s.Name(), Query: term, CacheHit: true,
+				Err: fmt.Errorf("falha recente (cache): %s", entry.Err)}
+		case !entry.Stale():
+			metrics.CacheRequests.WithLabelValues(s.Name(), "hit").Inc()
+			log.Info("cache hit", "jobs", len(entry.Jobs))
+			sendAll(emit, entry.Jobs)
+			return entry.Jobs, report.Search{Scraper: s.Name(), Query: term, Jobs: len(entry.Jobs), CacheHit: true}
+		default:
+			metrics.CacheRequests.WithLabelValues(s.Name(), "stale").Inc()
+			if e.opts.Background {
+				// Em processos residentes, responde já com o vencido e atualiza em segundo plano.
+				log.Info("cache vencido, atualizando em segundo plano", "jobs", len(entry.Jobs), "age", entry.Age().Round(time.Second))
+				e.refresh(ctx, s, term, loc)
+				sendAll(emit, entry.Jobs)
+				return entry.Jobs, report.Search{Scraper: s.Name(), Query: term, Jobs: len(entry.Jobs), CacheHit: true, Stale: true}
+			}
+			stale = &entry
+		}
+	}
+
+	jobs, stat := e.fetch(ctx, s, term, loc, emit)
+	if stat.Err != nil && !stat.Partial() && stale != nil {
+		log.Warn("busca falhou, usando resultado vencido do cache",
+			"jobs", len(stale.Jobs), "age", stale.Age().Round(time.Second), "err", stat.Err)
+		sendAll(emit, stale.Jobs)
+		return stale.Jobs, report.Search{Scraper: s.Name(), Query: term, Jobs: len(stale.Jobs),
+			CacheHit: true, Stale: true, Duration: stat.Duration}
+	}
+	e.store(ctx, log, s, term, loc, jobs, stat)
+	return jobs, stat
+}
+
+// fetch runs the search against the source, logging and measuring it.
+func (e *Engine) fetch(ctx context.Context, s scraper.Scraper, term, loc string, emit chan<- model.Job) ([]model.Job, report.Search) {
+	log := e.opts.Logger.With("scraper", s.Name(), "query", term)
+
+	log.Info("buscando")
+	start := time.Now()
+	jobs, err := scrape(ctx, s, term, loc, emit)
+	elapsed := time.Since(start)
+	metrics.ScrapeDuration.WithLabelValues(s.Name(), metrics.Result(err)).Observe(elapsed.Seconds())
+	metrics.ScrapeJobs.WithLabelValues(s.Name()).Add(float64(len(jobs)))
+
+	stat := report.Search{Scraper: s.Name(), Query: term, Jobs: len(jobs), Duration: elapsed, Err: err}
+	switch {
+	case stat.Partial():
+		log.Warn("busca interrompida, mantendo resultados parciais", "jobs", len(jobs), "err", err)
+		return jobs, stat
+	case stat.CircuitOpen():
+		log.Warn("fonte com circuito aberto, busca ignorada", "err", err)
+		return nil, stat
+	case err != nil:
+		log.Warn("busca falhou", "err", err)
+		return nil, stat
+	}
+	log.Info("busca concluída", "jobs", len(jobs), "duration", elapsed.Round(time.Millisecond))
+	return jobs, stat
+}
+
+// store caches the outcome of a search. Complete results are cached, empty
+// ones included; failures too, unless they were partial, caused by an open
+// circuit or by the search running out of time.
+func (e *Engine) store(ctx context.Context, log *slog.Logger, s scraper.Scraper, term, loc string, jobs []model.Job, stat report.Search) {
+	if e.opts.Cache == nil {
+		return
+	}
+	var err error
+	switch {
+	case stat.Err == nil:
+		err = e.opts.Cache.Set(ctx, s.Name(), term, loc, jobs)
+	case stat.Partial() || stat.CircuitOpen() || ctx.Err() != nil:
+		return
+	default:
+		err = e.opts.Cache.SetError(ctx, s.Name(), term, loc, stat.Err)
+	}
+	if err != nil {
+		log.Warn("falha ao salvar cache", "err", err)
+	}
+}
+
+// refresh re-runs a stale search in the background and caches the result.
+// Only one refresh per search runs at a time.
+func (e *Engine) refresh(ctx context.Context, s scraper.Scraper, term, loc string) {
+	key := s.Name() + "\x00" + term + "\x00" + loc
+	if _, busy := e.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+
+	e.refreshWG.Add(1)
+	go func() {
+		defer e.refreshWG.Done()
+		defer e.refreshing.Delete(key)
+
+		// Não herda o prazo da busca que o disparou, que já respondeu.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.opts.Timeout)
+		defer cancel()
+		jobs, stat := e.fetch(ctx, s, term, loc, nil)
+		if stat.Err == nil {
+			e.store(ctx, e.opts.Logger.With("scraper", s.Name(), "query", term), s, term, loc, jobs, stat)
+		}
+	}()
+}
+
+// scrape runs the search, streaming each page when the scraper supports it.
+// Every job leaves with its location normalized, before cache and filters.
+func scrape(ctx context.Context, s scraper.Scraper, term, loc string, emit chan<- model.Job) ([]model.Job, error) {
+	st, ok := s.(scraper.Streamer)
+	if emit == nil || !ok {
+		jobs, err := s.Search(ctx, term, loc)
+		for i := range jobs {
+			jobs[i] = locate(jobs[i])
+		}
+		sendAll(emit, jobs)
+		return jobs, err
+	}
+
+	page := make(chan model.Job)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- st.SearchStream(ctx, term, loc, page)
+		close(page)
+	}()
+
+	var jobs []model.Job
+	for j := range page {
+		j = locate(j)
+		jobs = append(jobs, j)
+		emit <- j
+	}
+	return jobs, <-errc
+}
+
+// locate fills the normalized location of a job from its Location.
+func locate(j model.Job) model.Job {
+	p := geo.Resolve(j.Location)
+	j.City, j.State, j.Country = p.City, p.State, p.Country
+	j.Remote = p.Remote || j.WorkModel == "remoto"
+	return j
+}
+
+// sendAll sends jobs on emit, if streaming.
+func sendAll(emit chan<- model.Job, jobs []model.Job) {
+	if emit == nil {
+		return
+	}
+	for _, j := range jobs {
+		emit <- j
+	}
+}
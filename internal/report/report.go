@@ -0,0 +1,183 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/httpclient"
+)
+
+// Exit codes returned by a one-shot run, so CI can tell failures apart.
+const (
+	ExitOK               = 0
+	ExitUsage            = 1 // flags ou configuração inválidas
+	ExitAllSourcesFailed = 2 // nenhuma busca teve sucesso
+	ExitPartialFailure   = 3 // parte das buscas falhou
+	ExitWriterFailed     = 4 // algum canal de notificação falhou
+)
+
+// Search is the outcome of one scraper/query pair.
+type Search struct {
+	Scraper  string
+	Query    string
+	Jobs     int
+	CacheHit bool
+	Stale    bool // resultado vencido do cache, servido no lugar da fonte
+	Duration time.Duration
+	Err      error
+}
+
+// Partial reports whether the search failed after fetching some jobs,
+// which were kept.
+func (s Search) Partial() bool {
+	return s.Err != nil && s.Jobs > 0
+}
+
+// CircuitOpen reports whether the search was skipped (or cut short)
+// because the source's host had its circuit breaker open.
+func (s Search) CircuitOpen() bool {
+	return errors.Is(s.Err, httpclient.ErrCircuitOpen)
+}
+
+// Summary describes a whole search run.
+type Summary struct {
+	Searches  []Search
+	Collected int          // vagas retornadas por todas as buscas
+	Unique    int          // após deduplicação
+	Matched   int          // após os filtros
+	Dropped   filter.Stats // descartadas por regra de filtro
+	Closed    int          // vagas detectadas como encerradas
+	// WriterErrors maps writer names to the error they returned.
+	WriterErrors map[string]error
+	Duration     time.Duration
+}
+
+// Failed returns how many searches ended with an error.
+func (s Summary) Failed() int {
+	n := 0
+	for _, sr := range s.Searches {
+		if sr.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// AllFailed reports whether every search failed without even partial results.
+func (s Summary) AllFailed() bool {
+	for _, sr := range s.Searches {
+		if sr.Err == nil || sr.Partial() {
+			return false
+		}
+	}
+	return len(s.Searches) > 0
+}
+
+// ExitCode maps the summary to a process exit code. All sources failing
+// takes precedence over a writer failure, which takes precedence over a
+// partial failure.
+func (s Summary) ExitCode() int {
+	switch {
+	case s.AllFailed():
+		return ExitAllSourcesFailed
+	case len(s.WriterErrors) > 0:
+		return ExitWriterFailed
+	case s.Failed() > 0:
+		return ExitPartialFailure
+	default:
+		return ExitOK
+	}
+}
+
+// Status returns a short human-readable verdict for the run.
+func (s Summary) Status() string {
+	switch s.ExitCode() {
+	case ExitAllSourcesFailed:
+		return "todas as fontes falharam"
+	case ExitWriterFailed:
+		return "falha ao notificar"
+	case ExitPartialFailure:
+		return "falha parcial"
+	default:
+		return "ok"
+	}
+}
+
+// WriteText renders the summary as plain-text tables.
+func (s Summary) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Resumo da execução (%s, %s):\n\n", s.Status(), s.Duration.Round(time.Millisecond))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FONTE\tBUSCA\tVAGAS\tCACHE\tDURACAO\tERRO")
+	fmt.Fprintln(tw, "-----\t-----\t-----\t-----\t-------\t----")
+	for _, sr := range s.sortedSearches() {
+		cache := "miss"
+		switch {
+		case sr.Stale:
+			cache = "vencido"
+		case sr.CacheHit:
+			cache = "hit"
+		}
+		errText := "-"
+		if sr.Err != nil {
+			errText = sr.Err.Error()
+		}
+		if sr.CircuitOpen() {
+			errText = "(circuito aberto) " + errText
+		}
+		if sr.Partial() {
+			errText = "(parcial) " + errText
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			sr.Scraper, sr.Query, sr.Jobs, cache, sr.Duration.Round(time.Millisecond), errText)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nColetadas: %d | Únicas: %d | Após filtros: %d", s.Collected, s.Unique, s.Matched)
+	if s.Closed > 0 {
+		fmt.Fprintf(w, " | Encerradas: %d", s.Closed)
+	}
+	fmt.Fprintln(w)
+
+	if s.Dropped.Dropped() > 0 {
+		fmt.Fprint(w, "Descartadas por filtro:")
+		for _, rule := range filter.Rules {
+			if n := s.Dropped[rule]; n > 0 {
+				fmt.Fprintf(w, " %s=%d", rule, n)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, name := range s.writerNames() {
+		fmt.Fprintf(w, "Falha no canal %s: %v\n", name, s.WriterErrors[name])
+	}
+	return nil
+}
+
+func (s Summary) sortedSearches() []Search {
+	out := append([]Search(nil), s.Searches...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Scraper != out[j].Scraper {
+			return out[i].Scraper < out[j].Scraper
+		}
+		return out[i].Query < out[j].Query
+	})
+	return out
+}
+
+func (s Summary) writerNames() []string {
+	names := make([]string, 0, len(s.WriterErrors))
+	for name := range s.WriterErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// bits returns the mask with the given values set.
+func bits(values ...int) uint64 {
+	var b uint64
+	for _, v := range values {
+		b |= 1 << uint(v)
+	}
+	return b
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     uint64
+		err      string // vazio: válido
+	}{
+		{field: "*", min: 0, max: 6, want: bits(0, 1, 2, 3, 4, 5, 6)},
+		{field: "5", min: 0, max: 59, want: bits(5)},
+		{field: "1,15", min: 1, max: 31, want: bits(1, 15)},
+		{field: "1-5", min: 0, max: 7, want: bits(1, 2, 3, 4, 5)},
+		{field: "*/15", min: 0, max: 59, want: bits(0, 15, 30, 45)},
+		{field: "8-18/4", min: 0, max: 23, want: bits(8, 12, 16)},
+		{field: "5/20", min: 0, max: 59, want: bits(5, 25, 45)},
+		{field: "1-3,10-20/5,30", min: 0, max: 59, want: bits(1, 2, 3, 10, 15, 20, 30)},
+		{field: "60", min: 0, max: 59, err: "out of range"},
+		{field: "0", min: 1, max: 31, err: "out of range"},
+		{field: "5-1", min: 0, max: 59, err: "out of range"},
+		{field: "*/0", min: 0, max: 59, err: "invalid step"},
+		{field: "*/x", min: 0, max: 59, err: "invalid step"},
+		{field: "a", min: 0, max: 59, err: "invalid value"},
+		{field: "1-b", min: 0, max: 59, err: "invalid value"},
+		{field: "1,", min: 0, max: 59, err: "invalid value"},
+	}
+	for _, tt := range tests {
+		got, err := parseField(tt.field, tt.min, tt.max)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseField(%q) error = %v, want %q", tt.field, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseField(%q) = %b, %v, want %b", tt.field, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{"", "expected 5 cron fields"},
+		{"* * * *", "expected 5 cron fields"},
+		{"* * * * * *", "expected 5 cron fields"},
+		{"@yearly", "expected 5 cron fields"},
+		{"60 * * * *", "minute"},
+		{"* 24 * * *", "hour"},
+		{"* * 32 * *", "day of month"},
+		{"* * * 13 *", "month"},
+		{"* * * * 8", "day of week"},
+		{"0 0 31 2 *", "never fires"},
+		{"0 0 30 2 *", "never fires"},
+		{"@every 30s", "shorter than 1m"},
+		{"@every seis horas", "invalid interval"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.spec); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.spec, err, tt.err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 1º de janeiro de 2024 foi uma segunda-feira.
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", at(1, 1, 10, 7), at(1, 1, 10, 15)},
+		{"*/15 * * * *", at(1, 1, 10, 45).Add(30 * time.Second), at(1, 1, 11, 0)},
+		{"@hourly", at(1, 1, 10, 0), at(1, 1, 11, 0)}, // estritamente depois
+		{"0 8,18 * * *", at(1, 1, 9, 0), at(1, 1, 18, 0)},
+		{"0 8-18/4 * * *", at(1, 1, 13, 0), at(1, 1, 16, 0)},
+		{"0 8-18/4 * * *", at(1, 1, 16, 0), at(1, 2, 8, 0)},
+		{"0 12 * * 1-5", at(1, 5, 13, 0), at(1, 8, 12, 0)}, // sexta à tarde -> segunda
+		{"0 9 * * 0", at(1, 1, 0, 0), at(1, 7, 9, 0)},
+		{"0 9 * * 7", at(1, 1, 0, 0), at(1, 7, 9, 0)}, // 7 também é domingo
+		{"@weekly", at(1, 1, 0, 0), at(1, 7, 0, 0)},
+
+		// Dia do mês e dia da semana restritos: vale qualquer um dos dois.
+		{"0 0 13 * 5", at(1, 1, 0, 0), at(1, 5, 0, 0)},   // sexta, dia 5
+		{"0 0 13 * 5", at(1, 12, 0, 0), at(1, 13, 0, 0)}, // sábado, dia 13
+		// Com um deles "*", só o outro restringe.
+		{"0 0 13 * *", at(1, 1, 0, 0), at(1, 13, 0, 0)},
+		{"0 0 * * 5", at(1, 6, 0, 0), at(1, 12, 0, 0)},
+
+		// Viradas de mês e de ano.
+		{"@monthly", at(1, 31, 10, 0), at(2, 1, 0, 0)},
+		{"0 0 31 * *", at(4, 1, 0, 0), at(5, 31, 0, 0)}, // abril não tem 31
+		{"59 23 * * *", at(2, 28, 23, 59), at(2, 29, 23, 59)},
+		{"30 23 31 12 *", at(12, 31, 23, 30), time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC)},
+		{"0 0 1 1 *", at(6, 1, 0, 0), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * 2 *", at(3, 1, 0, 0), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", at(3, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next(%s) = %s, want %s", tt.spec, tt.from.Format(time.DateTime), got.Format(time.DateTime), tt.want.Format(time.DateTime))
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	from := time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)
+	for spec, want := range map[string]time.Duration{
+		"@every 6h":   6 * time.Hour,
+		"@every 90m":  90 * time.Minute,
+		"@every 1m":   time.Minute,
+		" @every 2h ": 2 * time.Hour,
+	} {
+		s, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		if got := s.Next(from); !got.Equal(from.Add(want)) {
+			t.Errorf("%q: Next = %s, want %s", spec, got, from.Add(want))
+		}
+	}
+}
+
+func TestJobNextJitter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	const jitter = 10 * time.Minute
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		base     time.Time
+	}{
+		{"every", Every(time.Hour), now.Add(time.Hour)},
+		{"cron", cronOf(t, "30 * * * *"), now.Add(30 * time.Minute)},
+	}
+	for _, tt := range tests {
+		job := Job{Schedule: tt.schedule, Jitter: jitter}
+		var top time.Duration
+		for range 1000 {
+			d := job.next(now).Sub(tt.base)
+			if d < 0 || d >= jitter {
+				t.Fatalf("%s: activation %v after the schedule, want within [0, %v)", tt.name, d, jitter)
+			}
+			top = max(top, d)
+		}
+		if top < jitter/2 {
+			t.Errorf("%s: jitter never above %v in 1000 draws", tt.name, top)
+		}
+
+		job.Jitter = 0
+		if got := job.next(now); !got.Equal(tt.base) {
+			t.Errorf("%s: next without jitter = %s, want %s", tt.name, got, tt.base)
+		}
+	}
+}
+
+func cronOf(t *testing.T, spec string) Schedule {
+	t.Helper()
+	s, err := Parse(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Job is a recurring task run by the Scheduler.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter adds a random delay in [0, Jitter) to every activation, so
+	// several instances (or profiles) don't hit the same host in lockstep.
+	Jitter time.Duration
+	// RunOnStart runs the job once as soon as the scheduler starts.
+	RunOnStart bool
+	Run        func(ctx context.Context)
+}
+
+// Scheduler runs jobs on their schedules until its context is canceled.
+// Activations of the same job never overlap: a run that takes longer than
+// the interval delays the next one.
+type Scheduler struct {
+	jobs []Job
+}
+
+// New creates an empty Scheduler.
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add registers a job. Must be called before Run.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Run starts every job and blocks until ctx is canceled and all in-flight
+// runs have returned. Runs receive a context that is not canceled on
+// shutdown, so a search in progress can finish and notify cleanly.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.jobs) == 0 {
+		return fmt.Errorf("scheduler: no jobs registered")
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	runCtx := context.WithoutCancel(ctx)
+	if job.RunOnStart {
+		job.Run(runCtx)
+	}
+
+	for {
+		next := job.next(time.Now())
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		job.Run(runCtx)
+	}
+}
+
+// next returns the activation after now, jitter included; zero if the
+// schedule never fires again.
+func (job Job) next(now time.Time) time.Time {
+	next := job.Schedule.Next(now)
+	if next.IsZero() || job.Jitter <= 0 {
+		return next
+	}
+	return next.Add(time.Duration(rand.Int63n(int64(job.Jitter))))
+}
@@ -38,21 +38,89 @@ type gupyJob struct {
 
 type Gupy struct {
 	client *httpclient.Client
+	pages  int
 }
 
-func NewGupy(client *httpclient.Client) *Gupy {
-	return &Gupy{client: client}
+// NewGupy returns a Gupy scraper that walks at most pages result pages per
+// search; pages < 1 means one.
+func NewGupy(client *httpclient.Client, pages int) *Gupy {
+	return &Gupy{client: client, pages: max(pages, 1)}
 }
 
 func (g *Gupy) Name() string {
 	return "Gupy"
 }
 
+// Search walks the result pages until a short page or the page limit. If a
+// page fails, the jobs from the previous pages are returned with the error.
 func (g *Gupy) Search(ctx context.Context, query string, location string) ([]model.Job, error) {
+	var jobs []model.Job
+	err := g.walk(ctx, query, location, func(j model.Job) error {
+		jobs = append(jobs, j)
+		return nil
+	})
+	return jobs, err
+}
+
+// SearchStream is like Search but sends the jobs of each page to out as
+// soon as the page is decoded.
+func (g *Gupy) SearchStream(ctx context.Context, query string, location string, out chan<- model.Job) error {
+	return g.walk(ctx, query, location, func(j model.Job) error {
+		select {
+		case out <- j:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// walk fetches the result pages and calls emit for every job that matches
+// location.
+func (g *Gupy) walk(ctx context.Context, query, location string, emit func(model.Job) error) error {
+	for page := range g.pages {
+		data, err := g.fetchPage(ctx, query, page*gupyPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, gj := range data {
+			loc := buildLocation(gj.City, gj.State, gj.Country)
+
+			// Filtrar por localização se informada.
+			if location != "" && !strings.Contains(strings.ToLower(loc), strings.ToLower(location)) {
+				continue
+			}
+
+			posted, _ := time.Parse(time.RFC3339, gj.PublishedDate)
+
+			err := emit(model.Job{
+				Title:     gj.Name,
+				Company:   gj.CareerPage,
+				Location:  loc,
+				URL:       gj.JobURL,
+				Source:    "gupy",
+				PostedAt:  posted,
+				WorkModel: mapWorkplaceType(gj.WorkplaceType, gj.IsRemoteWork),
+				JobType:   mapJobType(gj.Type),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(data) < gupyPageSize {
+			break
+		}
+	}
+	return nil
+}
+
+func (g *Gupy) fetchPage(ctx context.Context, query string, offset int) ([]gupyJob, error) {
 	params := url.Values{}
 	params.Set("jobName", query)
 	params.Set("limit", fmt.Sprintf("%d", gupyPageSize))
-	params.Set("offset", "0")
+	params.Set("offset", fmt.Sprintf("%d", offset))
 	searchURL := fmt.Sprintf("%s?%s", gupyAPIURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
@@ -63,43 +131,19 @@ func (g *Gupy) Search(ctx context.Context, query string, location string) ([]mod
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("gupy: executing request: %w", err)
+		return nil, fmt.Errorf("gupy: executing request (offset %d): %w", offset, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("gupy: unexpected status %d", resp.StatusCode)
+		return nil, fmt.Errorf("gupy: unexpected status %d (offset %d)", resp.StatusCode, offset)
 	}
 
 	var result gupyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("gupy: decoding response: %w", err)
-	}
-
-	var jobs []model.Job
-	for _, gj := range result.Data {
-		loc := buildLocation(gj.City, gj.State, gj.Country)
-
-		// Filtrar por localização se informada.
-		if location != "" && !strings.Contains(strings.ToLower(loc), strings.ToLower(location)) {
-			continue
-		}
-
-		posted, _ := time.Parse(time.RFC3339, gj.PublishedDate)
-
-		jobs = append(jobs, model.Job{
-			Title:     gj.Name,
-			Company:   gj.CareerPage,
-			Location:  loc,
-			URL:       gj.JobURL,
-			Source:    "gupy",
-			PostedAt:  posted,
-			WorkModel: mapWorkplaceType(gj.WorkplaceType, gj.IsRemoteWork),
-			JobType:   mapJobType(gj.Type),
-		})
+		return nil, fmt.Errorf("gupy: decoding response (offset %d): %w", offset, err)
 	}
-
-	return jobs, nil
+	return result.Data, nil
 }
 
 func buildLocation(city, state, country string) string {
@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/httpclient"
+	"github.com/rsilvagit/go-work/internal/model"
+)
+
+// testdata/gupy holds a recorded search for "golang": a full page of 20 jobs
+// and a last page of 3, plus the robots.txt.
+func replayClient(t *testing.T) *httpclient.Client {
+	t.Helper()
+	c, err := httpclient.New(httpclient.Options{
+		ReplayDir: "testdata/gupy",
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGupySearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		location string
+		pages    int
+		want     int
+		err      error
+	}{
+		{name: "one page by default", query: "golang", want: 20},
+		{name: "stops at a short page", query: "golang", pages: 5, want: 23},
+		{name: "location filter", query: "golang", location: "curitiba", pages: 2, want: 4},
+		{name: "no match", query: "golang", location: "Manaus", pages: 2, want: 0},
+		{name: "not recorded", query: "rust", err: httpclient.ErrNotRecorded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := NewGupy(replayClient(t), tt.pages).Search(context.Background(), tt.query, tt.location)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Search error = %v, want %v", err, tt.err)
+			}
+			if len(jobs) != tt.want {
+				t.Errorf("Search returned %d jobs, want %d", len(jobs), tt.want)
+			}
+			for _, j := range jobs {
+				if j.Source != "gupy" || j.URL == "" {
+					t.Errorf("job %+v: missing source or URL", j)
+				}
+			}
+		})
+	}
+}
+
+func TestGupyJobFields(t *testing.T) {
+	jobs, err := NewGupy(replayClient(t), 1).Search(context.Background(), "golang", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		i    int
+		want model.Job
+	}{
+		{0, model.Job{
+			Title: "Desenvolvedor(a) Go Pleno", Company: "Nuvem Tech", Location: "Brasil",
+			URL: "https://nuvemtech.gupy.io/jobs/8100000", Source: "gupy",
+			PostedAt:  time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+			WorkModel: "remoto", JobType: "full-time",
+		}},
+		{2, model.Job{
+			Title: "Desenvolvedor(a) Go Júnior", Company: "Banco Aurora", Location: "Curitiba, Paraná, Brasil",
+			URL: "https://bancoaurora.gupy.io/jobs/8100074", Source: "gupy",
+			PostedAt:  time.Date(2026, 10, 3, 12, 0, 0, 0, time.UTC),
+			WorkModel: "presencial", JobType: "estagio",
+		}},
+		{4, model.Job{
+			Title: "Desenvolvedor(a) Go Pleno", Company: "Loja Azul", Location: "Florianópolis, Santa Catarina, Brasil",
+			URL: "https://lojaazul.gupy.io/jobs/8100148", Source: "gupy",
+			PostedAt:  time.Date(2026, 10, 5, 14, 0, 0, 0, time.UTC),
+			WorkModel: "hibrido", JobType: "part-time",
+		}},
+	}
+	for _, tt := range tests {
+		got := jobs[tt.i]
+		if !got.PostedAt.Equal(tt.want.PostedAt) {
+			t.Errorf("job %d: PostedAt = %v, want %v", tt.i, got.PostedAt, tt.want.PostedAt)
+		}
+		got.PostedAt = tt.want.PostedAt
+		if got != tt.want {
+			t.Errorf("job %d = %+v, want %+v", tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestGupySearchStream(t *testing.T) {
+	out := make(chan model.Job)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- NewGupy(replayClient(t), 2).SearchStream(context.Background(), "golang", "são paulo", out)
+		close(out)
+	}()
+
+	var n int
+	for j := range out {
+		if j.Location != "São Paulo, São Paulo, Brasil" {
+			t.Errorf("streamed job in %q", j.Location)
+		}
+		n++
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("streamed %d jobs, want 4", n)
+	}
+}
+
+// Uma request sem gravação não pode abrir o circuito da Gupy.
+func TestGupyNotRecordedKeepsCircuitClosed(t *testing.T) {
+	g := NewGupy(replayClient(t), 2)
+	ctx := context.Background()
+
+	for _, q := range []string{"rust", "java", "python", "kotlin", "elixir", "scala", "ruby"} {
+		if _, err := g.Search(ctx, q, ""); !errors.Is(err, httpclient.ErrNotRecorded) {
+			t.Fatalf("Search(%q) error = %v, want ErrNotRecorded", q, err)
+		}
+	}
+	jobs, err := g.Search(ctx, "golang", "")
+	if err != nil {
+		t.Fatalf("Search after unrecorded requests: %v", err)
+	}
+	if len(jobs) != 23 {
+		t.Errorf("Search returned %d jobs, want 23", len(jobs))
+	}
+}
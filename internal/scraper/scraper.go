@@ -13,12 +13,32 @@ type Scraper interface {
 	Name() string
 
 	// Search queries the job site and returns matching listings.
+	//
+	// When the search stops early (deadline, network error, bad page), Search
+	// returns the jobs fetched so far together with the error. Callers must
+	// treat a non-nil error with a non-empty slice as a partial result.
 	Search(ctx context.Context, query string, location string) ([]model.Job, error)
 }
 
+// Streamer is implemented by scrapers that can emit jobs as each result
+// page arrives, instead of only when the whole search ends.
+type Streamer interface {
+	// SearchStream sends every job to out as soon as it is parsed. It follows
+	// the same partial-result contract as Search: jobs already sent stay
+	// valid when an error is returned. SearchStream does not close out.
+	SearchStream(ctx context.Context, query string, location string, out chan<- model.Job) error
+}
+
+// Options configures the scrapers returned by Registry.
+type Options struct {
+	// Pages is the maximum number of result pages a search walks in each
+	// source. Every page is one more request to the site. Default 1.
+	Pages int
+}
+
 // Registry returns all available scrapers using the shared HTTP client.
-func Registry(client *httpclient.Client) []Scraper {
+func Registry(client *httpclient.Client, opts Options) []Scraper {
 	return []Scraper{
-		NewGupy(client),
+		NewGupy(client, opts.Pages),
 	}
 }
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/filter"
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/output"
+	"github.com/rsilvagit/go-work/internal/pipeline"
+)
+
+// SearchRequest is a search parsed from the query string of GET /jobs.
+type SearchRequest struct {
+	Queries  []string
+	Location string
+	Filter   filter.Options
+}
+
+// SearchFunc runs the scraper fan-out, dedup and filter pipeline.
+type SearchFunc func(ctx context.Context, req SearchRequest) ([]model.Job, error)
+
+// EngineSearch adapts the search pipeline to the HTTP API. Requests never
+// notify the chat writers.
+func EngineSearch(e *pipeline.Engine) SearchFunc {
+	return func(ctx context.Context, req SearchRequest) ([]model.Job, error) {
+		res, err := e.Run(ctx, pipeline.SearchRequest{
+			Queries:  req.Queries,
+			Location: req.Location,
+			Filter:   req.Filter,
+		})
+		if errors.Is(err, pipeline.ErrAllFailed) {
+			return nil, fmt.Errorf("todas as fontes falharam: %w", res.Summary.Searches[0].Err)
+		}
+		return res.Jobs, nil
+	}
+}
+
+// Options configures the HTTP API server.
+type Options struct {
+	Addr    string
+	Timeout time.Duration // deadline for each /jobs request
+	Sources []string      // names listed by /sources
+	Metrics http.Handler  // served on /metrics when set
+}
+
+func (o Options) withDefaults() Options {
+	if o.Addr == "" {
+		o.Addr = ":8080"
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 30 * time.Second
+	}
+	return o
+}
+
+// Server exposes the search pipeline over HTTP:
+//
+//	GET /jobs?q=golang&modelo=remoto&nivel=senior
+//	GET /sources
+//	GET /healthz
+//	GET /metrics (when Options.Metrics is set)
+type Server struct {
+	search  SearchFunc
+	opts    Options
+	handler http.Handler
+}
+
+// New creates a Server that answers /jobs with search.
+func New(search SearchFunc, opts Options) *Server {
+	s := &Server{search: search, opts: opts.withDefaults()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", s.handleJobs)
+	mux.HandleFunc("GET /sources", s.handleSources)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	if s.opts.Metrics != nil {
+		mux.Handle("GET /metrics", s.opts.Metrics)
+	}
+	s.handler = mux
+	return s
+}
+
+// Handler returns the HTTP handler, for use with httptest or a custom server.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// ListenAndServe serves until ctx is canceled, then shuts down gracefully,
+// letting in-flight requests finish.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.opts.Addr,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		// A escrita inclui a busca inteira; margem para serializar a resposta.
+		WriteTimeout: s.opts.Timeout + 10*time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server: shutdown: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server: %w", err)
+	}
+	return nil
+}
+
+type jobsResponse struct {
+	Total int              `json:"total"`
+	Jobs  []output.JobJSON `json:"jobs"`
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	req, err := parseSearchRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.Timeout)
+	defer cancel()
+
+	jobs, err := s.search(ctx, req)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusGatewayTimeout
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	resp := jobsResponse{Total: len(jobs), Jobs: make([]output.JobJSON, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, output.ToJSON(j))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleSources(w http.ResponseWriter, r *http.Request) {
+	sources := s.opts.Sources
+	if sources == nil {
+		sources = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"sources": sources})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// parseSearchRequest reads the same parameters as the CLI flags:
+// q, l, tipo, modelo, nivel, regiao, uf and cidade.
+func parseSearchRequest(r *http.Request) (SearchRequest, error) {
+	v := r.URL.Query()
+
+	var queries []string
+	for _, term := range strings.Split(v.Get("q"), ",") {
+		term = strings.TrimSpace(term)
+		if term != "" {
+			queries = append(queries, term)
+		}
+	}
+	if len(queries) == 0 {
+		return SearchRequest{}, fmt.Errorf("parâmetro q é obrigatório")
+	}
+
+	opts := filter.Options{
+		JobType:   v.Get("tipo"),
+		WorkModel: v.Get("modelo"),
+		Level:     v.Get("nivel"),
+		Region:    v.Get("regiao"),
+		State:     v.Get("uf"),
+		City:      v.Get("cidade"),
+	}
+	if err := opts.Validate(); err != nil {
+		// As regras têm o nome dos parâmetros da query string.
+		var verr *filter.ValidationError
+		if errors.As(err, &verr) {
+			return SearchRequest{}, fmt.Errorf("parâmetro %s inválido: %w", verr.Rule, err)
+		}
+		return SearchRequest{}, fmt.Errorf("filtro inválido: %w", err)
+	}
+
+	return SearchRequest{
+		Queries:  queries,
+		Location: v.Get("l"),
+		Filter:   opts,
+	}, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
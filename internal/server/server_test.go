@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rsilvagit/go-work/internal/model"
+	"github.com/rsilvagit/go-work/internal/pipeline"
+	"github.com/rsilvagit/go-work/internal/scraper"
+)
+
+// fakeScraper returns fixed jobs and error, recording the searches it got.
+// With block, it waits for the search deadline instead.
+type fakeScraper struct {
+	name  string
+	jobs  []model.Job
+	err   error
+	block bool
+
+	mu       sync.Mutex
+	searches []string // "termo|local"
+}
+
+func (s *fakeScraper) Name() string { return s.name }
+
+func (s *fakeScraper) Search(ctx context.Context, query, location string) ([]model.Job, error) {
+	s.mu.Lock()
+	s.searches = append(s.searches, query+"|"+location)
+	s.mu.Unlock()
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return slices.Clone(s.jobs), s.err
+}
+
+var posted = time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+func job(title, url, location, workModel, level string) model.Job {
+	return model.Job{
+		Title: title, Company: "ACME", URL: url, Location: location, Source: "gupy",
+		PostedAt: posted, WorkModel: workModel, Level: level,
+	}
+}
+
+var (
+	jobSP = job("Vaga A", "https://acme.example/1", "São Paulo, SP", "remoto", "senior")
+	jobPR = job("Vaga B", "https://acme.example/2", "Curitiba, PR", "presencial", "pleno")
+	jobRJ = job("Vaga C", "https://acme.example/3", "Rio de Janeiro, RJ", "hibrido", "senior")
+)
+
+// newHandler serves /jobs from a real engine over the scrapers, through the
+// same adapter as "go-work serve".
+func newHandler(t *testing.T, timeout time.Duration, scrapers ...scraper.Scraper) http.Handler {
+	t.Helper()
+	e := pipeline.New(pipeline.Options{
+		Scrapers: scrapers,
+		Quiet:    true,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	t.Cleanup(e.Close)
+	return New(EngineSearch(e), Options{Timeout: timeout, Sources: e.Sources()}).Handler()
+}
+
+// get requests path and decodes the JSON body into v.
+func get(t *testing.T, h http.Handler, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("GET %s: Content-Type = %q, want JSON", path, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: decoding %s: %v", path, rec.Body, err)
+	}
+	return rec.Code
+}
+
+func TestJobs(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string // títulos, em ordem
+	}{
+		{name: "duplicates collapsed", query: "q=golang", want: []string{"Vaga A", "Vaga B", "Vaga C"}},
+		{name: "duplicates across queries", query: "q=golang,go", want: []string{"Vaga A", "Vaga B", "Vaga C"}},
+		{name: "modelo", query: "q=golang&modelo=remoto", want: []string{"Vaga A"}},
+		{name: "modelo list", query: "q=golang&modelo=remoto,hibrido", want: []string{"Vaga A", "Vaga C"}},
+		{name: "nivel", query: "q=golang&nivel=senior", want: []string{"Vaga A", "Vaga C"}},
+		{name: "uf", query: "q=golang&uf=PR", want: []string{"Vaga B"}},
+		{name: "uf by name", query: "q=golang&uf=rio+de+janeiro,sp", want: []string{"Vaga A", "Vaga C"}},
+		{name: "cidade", query: "q=golang&cidade=curitiba", want: []string{"Vaga B"}},
+		{name: "combined", query: "q=golang&modelo=hibrido&nivel=senior&uf=RJ", want: []string{"Vaga C"}},
+		{name: "nothing left", query: "q=golang&uf=AM", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHandler(t, time.Minute,
+				&fakeScraper{name: "gupy", jobs: []model.Job{jobSP, jobPR}},
+				&fakeScraper{name: "vagas", jobs: []model.Job{jobSP, jobRJ}},
+			)
+
+			var body struct {
+				Total int
+				Jobs  []struct{ Title string }
+			}
+			if code := get(t, h, "/jobs?"+tt.query, &body); code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", code)
+			}
+			got := []string{}
+			for _, j := range body.Jobs {
+				got = append(got, j.Title)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("jobs = %v, want %v", got, tt.want)
+			}
+			if body.Total != len(tt.want) {
+				t.Errorf("total = %d, want %d", body.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestJobsJSON(t *testing.T) {
+	h := newHandler(t, time.Minute, &fakeScraper{name: "gupy", jobs: []model.Job{jobSP, jobPR}})
+
+	var body struct{ Jobs []map[string]any }
+	if code := get(t, h, "/jobs?q=golang&uf=SP", &body); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(body.Jobs) != 1 {
+		t.Fatalf("jobs = %v, want 1", body.Jobs)
+	}
+	want := map[string]any{
+		"title":     "Vaga A",
+		"company":   "ACME",
+		"location":  "São Paulo, SP",
+		"url":       "https://acme.example/1",
+		"source":    "gupy",
+		"posted_at": posted.Format(time.RFC3339),
+		"modelo":    "remoto",
+		"nivel":     "senior",
+		"cidade":    "São Paulo",
+		"uf":        "SP",
+		"pais":      "Brasil",
+		"remoto":    true,
+	}
+	got := body.Jobs[0]
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("jobs[0].%s = %v, want %v", k, got[k], v)
+		}
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			t.Errorf("unexpected field jobs[0].%s", k)
+		}
+	}
+}
+
+func TestJobsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		scraper    *fakeScraper
+		wantStatus int
+		wantError  string // trecho da mensagem de erro
+		wantCalled bool
+	}{
+		{name: "missing q", query: "", wantStatus: http.StatusBadRequest, wantError: "q é obrigatório"},
+		{name: "blank q", query: "q=+,+", wantStatus: http.StatusBadRequest, wantError: "q é obrigatório"},
+		{name: "bad uf", query: "q=golang&uf=XX", wantStatus: http.StatusBadRequest, wantError: `parâmetro uf inválido: filter: uf "XX"`},
+		{name: "bad uf in list", query: "q=golang&uf=SP,+ZZ", wantStatus: http.StatusBadRequest, wantError: `uf "ZZ"`},
+		{
+			name: "all sources failed", query: "q=golang",
+			scraper:    &fakeScraper{err: errors.New("fonte fora do ar")},
+			wantStatus: http.StatusBadGateway, wantError: "todas as fontes falharam: fonte fora do ar", wantCalled: true,
+		},
+		{
+			name: "deadline", query: "q=golang",
+			scraper:    &fakeScraper{block: true},
+			wantStatus: http.StatusGatewayTimeout, wantError: "deadline", wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.scraper
+			if s == nil {
+				s = &fakeScraper{}
+			}
+			s.name = "gupy"
+			h := newHandler(t, 50*time.Millisecond, s)
+
+			var body struct{ Error string }
+			if code := get(t, h, "/jobs?"+tt.query, &body); code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", code, tt.wantStatus)
+			}
+			if !strings.Contains(body.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to mention %q", body.Error, tt.wantError)
+			}
+			if called := len(s.searches) > 0; called != tt.wantCalled {
+				t.Errorf("scraper called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestJobsRequest(t *testing.T) {
+	s := &fakeScraper{name: "gupy"}
+	h := newHandler(t, time.Minute, s)
+
+	var body struct{ Total int }
+	if code := get(t, h, "/jobs?q=golang,+rust+&l=Brasil", &body); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	slices.Sort(s.searches)
+	if want := []string{"golang|Brasil", "rust|Brasil"}; !slices.Equal(s.searches, want) {
+		t.Errorf("searches = %q, want %q", s.searches, want)
+	}
+}
+
+func TestSourcesAndHealthz(t *testing.T) {
+	tests := []struct {
+		path     string
+		scrapers []scraper.Scraper
+		want     string
+	}{
+		{"/sources", []scraper.Scraper{&fakeScraper{name: "gupy"}, &fakeScraper{name: "vagas"}}, `{"sources":["gupy","vagas"]}`},
+		{"/sources", nil, `{"sources":[]}`},
+		{"/healthz", nil, `{"status":"ok"}`},
+	}
+	for _, tt := range tests {
+		h := newHandler(t, time.Minute, tt.scrapers...)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", tt.path, rec.Code)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+			t.Errorf("%s: body = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	h := New(nil, Options{}).Handler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs?q=golang", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// File keeps one JSON file per entry in a directory, so the stored values
+// survive between runs without Redis (e.g. a cached CI directory). Values
+// must be JSON documents.
+type File struct {
+	dir string
+}
+
+type fileItem struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// NewFile returns a store in dir, creating it if needed.
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("store: creating dir: %w", err)
+	}
+	return &File{dir: dir}, nil
+}
+
+// path names the file of key after the key itself, so the directory can be
+// browsed; keys with other characters than letters, digits, ".", "_", "-"
+// and ":" are hashed.
+func (s *File) path(key string) string {
+	name := strings.ReplaceAll(key, ":", "_")
+	if strings.IndexFunc(key, unsafeRune) >= 0 {
+		name = fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+	}
+	return filepath.Join(s.dir, name+".json")
+}
+
+func unsafeRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return false
+	case r == '.', r == '_', r == '-', r == ':':
+		return false
+	}
+	return true
+}
+
+func (s *File) Get(_ context.Context, key string) ([]byte, bool, error) {
+	p := s.path(key)
+
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("store: reading entry: %w", err)
+	}
+
+	var item fileItem
+	if err := json.Unmarshal(data, &item); err != nil || time.Now().After(item.Expires) {
+		os.Remove(p)
+		return nil, false, nil
+	}
+	return item.Value, true, nil
+}
+
+func (s *File) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("store: file values must be JSON")
+	}
+	data, err := json.Marshal(fileItem{Key: key, Expires: time.Now().Add(ttl), Value: value})
+	if err != nil {
+		return fmt.Errorf("store: marshal error: %w", err)
+	}
+
+	// Grava em um arquivo temporário e renomeia, para nunca ler um arquivo pela metade.
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("store: writing entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: writing entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: writing entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Scan reads every entry file in the directory; expired ones are removed.
+// Files written before entries recorded their key get it back from the file
+// name, so they can still be listed and deleted.
+func (s *File) Scan(_ context.Context, pattern string, fn func(key string, value []byte, expires time.Time) error) error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("store: listing entries: %w", err)
+	}
+	now := time.Now()
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue // removida por outro processo
+		}
+		var item fileItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			continue
+		}
+		if item.Key == "" {
+			item.Key = strings.ReplaceAll(strings.TrimSuffix(filepath.Base(p), ".json"), "_", ":")
+		}
+		if ok, _ := path.Match(pattern, item.Key); !ok {
+			continue
+		}
+		if now.After(item.Expires) {
+			os.Remove(p)
+			continue
+		}
+		if err := fn(item.Key, item.Value, item.Expires); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *File) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("store: deleting entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; it satisfies Store.
+func (s *File) Close() error {
+	return nil
+}
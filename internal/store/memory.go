@@ -0,0 +1,121 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"path"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// cost is what the item counts against the byte budget.
+func (item *memoryItem) cost() int64 {
+	return int64(len(item.key) + len(item.value))
+}
+
+// Memory is an in-process LRU store bounded by the bytes of its keys and
+// values, and optionally by entry count. It suits the serve mode, where the
+// process outlives many searches.
+type Memory struct {
+	maxBytes int64
+	size     int
+
+	mu    sync.Mutex
+	used  int64      // bytes das entradas guardadas
+	order *list.List // mais recente na frente
+	items map[string]*list.Element
+}
+
+// NewMemory returns a store holding at most maxBytes of keys and values and
+// at most size entries; a limit that is not positive is not enforced. A
+// value larger than maxBytes on its own is not stored.
+func NewMemory(maxBytes int64, size int) *Memory {
+	return &Memory{maxBytes: maxBytes, size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (s *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := el.Value.(*memoryItem)
+	if time.Now().After(item.expires) {
+		s.remove(el)
+		return nil, false, nil
+	}
+	s.order.MoveToFront(el)
+	return item.value, true, nil
+}
+
+func (s *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	item := &memoryItem{key: key, value: value, expires: time.Now().Add(ttl)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+	if s.maxBytes > 0 && item.cost() > s.maxBytes {
+		return nil // maior que o cache inteiro
+	}
+	s.items[key] = s.order.PushFront(item)
+	s.used += item.cost()
+	for (s.size > 0 && s.order.Len() > s.size) || (s.maxBytes > 0 && s.used > s.maxBytes) {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// remove drops el from the list and the index; s.mu must be held.
+func (s *Memory) remove(el *list.Element) {
+	item := s.order.Remove(el).(*memoryItem)
+	delete(s.items, item.key)
+	s.used -= item.cost()
+}
+
+func (s *Memory) Scan(_ context.Context, pattern string, fn func(key string, value []byte, expires time.Time) error) error {
+	s.mu.Lock()
+	var items []memoryItem
+	now := time.Now()
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		item := el.Value.(*memoryItem)
+		if ok, _ := path.Match(pattern, item.key); ok && now.Before(item.expires) {
+			items = append(items, *item)
+		}
+	}
+	s.mu.Unlock()
+
+	// fn roda sem o lock, para poder chamar Delete.
+	for _, item := range items {
+		if err := fn(item.key, item.value, item.expires); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Memory) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := s.items[key]; ok {
+			s.remove(el)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; it satisfies Store.
+func (s *Memory) Close() error {
+	return nil
+}
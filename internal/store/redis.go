@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps entries in Redis, shared by every instance.
+type Redis struct {
+	client *redis.Client
+	owned  bool // aberto por Open, que também o fecha
+}
+
+// DialRedis connects to Redis at the given URL, like
+// "redis://localhost:6379", and checks the connection.
+func DialRedis(redisURL string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("store: invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("store: redis ping failed: %w", err)
+	}
+	return client, nil
+}
+
+// NewRedis returns a store over client, which the caller closes; it lets
+// the caches share one connection with the rest of the process.
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (s *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (s *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *Redis) Scan(ctx context.Context, pattern string, fn func(key string, value []byte, expires time.Time) error) error {
+	iter := s.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		pipe := s.client.Pipeline()
+		get, ttl := pipe.Get(ctx, key), pipe.PTTL(ctx, key)
+		if _, err := pipe.Exec(ctx); errors.Is(err, redis.Nil) {
+			continue // expirou durante o scan
+		} else if err != nil {
+			return err
+		}
+		data, _ := get.Bytes()
+		if err := fn(key, data, time.Now().Add(ttl.Val())); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (s *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// Close closes the Redis connection if Open created it.
+func (s *Redis) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.client.Close()
+}
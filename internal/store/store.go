@@ -0,0 +1,118 @@
+// Package store provides the key/value backends shared by the job result
+// cache and the HTTP response cache: an in-process LRU, a directory of JSON
+// files and Redis.
+package store
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMemoryBytes is the byte budget of a "mem://" store without ?bytes=.
+	DefaultMemoryBytes = 64 << 20
+	// DefaultMemorySize is the entry limit of a "mem://" store without ?size=.
+	DefaultMemorySize = 1000
+)
+
+// Store keeps values that expire after a ttl.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Scan calls fn for every live key matching pattern, with its value and
+	// expiry. pattern is a glob with *, ? and [...], as understood by Redis
+	// SCAN and path.Match; keys never contain "/".
+	Scan(ctx context.Context, pattern string, fn func(key string, value []byte, expires time.Time) error) error
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
+
+// Open opens the store described by spec:
+//
+//	redis://localhost:6379   Redis, shared by every instance
+//	mem://?bytes=32MB        in-process LRU, lost when the process exits
+//	file:///var/cache/gowork JSON files, kept between runs
+func Open(spec string) (Store, error) {
+	scheme, rest, _ := strings.Cut(spec, "://")
+	switch scheme {
+	case "redis", "rediss":
+		client, err := DialRedis(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &Redis{client: client, owned: true}, nil
+	case "mem", "memory":
+		maxBytes, size, err := memoryLimits(rest)
+		if err != nil {
+			return nil, fmt.Errorf("store: invalid limit in %q: %w", spec, err)
+		}
+		return NewMemory(maxBytes, size), nil
+	case "file":
+		if rest == "" {
+			return nil, fmt.Errorf("store: file store needs a directory, like file:///var/cache/go-work")
+		}
+		return NewFile(rest)
+	default:
+		return nil, fmt.Errorf("store: unknown backend %q (use redis://, mem:// or file://)", spec)
+	}
+}
+
+// IsRedis reports whether spec names a Redis store.
+func IsRedis(spec string) bool {
+	return strings.HasPrefix(spec, "redis://") || strings.HasPrefix(spec, "rediss://")
+}
+
+// memoryLimits reads ?bytes= and ?size= from the part of a mem:// spec
+// after the scheme.
+func memoryLimits(rest string) (maxBytes int64, size int, err error) {
+	_, query, _ := strings.Cut(rest, "?")
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	maxBytes, size = DefaultMemoryBytes, DefaultMemorySize
+	if v := q.Get("bytes"); v != "" {
+		if maxBytes, err = parseBytes(v); err != nil {
+			return 0, 0, err
+		}
+	}
+	if v := q.Get("size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil {
+			return 0, 0, err
+		}
+		if size <= 0 {
+			return 0, 0, fmt.Errorf("size must be positive")
+		}
+	}
+	return maxBytes, size, nil
+}
+
+// parseBytes reads a byte count such as "1048576", "512KB" or "64MB"; the
+// units are powers of 1024.
+func parseBytes(v string) (int64, error) {
+	units := []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	num, mult := strings.ToUpper(strings.TrimSpace(v)), int64(1)
+	for _, u := range units {
+		if n, ok := strings.CutSuffix(num, u.suffix); ok {
+			num, mult = strings.TrimSpace(n), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad byte count %q", v)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("bytes must be positive")
+	}
+	return n * mult, nil
+}
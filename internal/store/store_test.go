@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		spec    string
+		want    string // tipo esperado, ou "" para erro
+		bytes   int64
+		size    int
+		wantErr string
+	}{
+		{spec: "mem://", want: "*store.Memory", bytes: DefaultMemoryBytes, size: DefaultMemorySize},
+		{spec: "memory://", want: "*store.Memory", bytes: DefaultMemoryBytes, size: DefaultMemorySize},
+		{spec: "mem://?size=5", want: "*store.Memory", bytes: DefaultMemoryBytes, size: 5},
+		{spec: "mem://?bytes=4096", want: "*store.Memory", bytes: 4096, size: DefaultMemorySize},
+		{spec: "mem://?bytes=512kb&size=20", want: "*store.Memory", bytes: 512 << 10, size: 20},
+		{spec: "mem://?bytes=32MB", want: "*store.Memory", bytes: 32 << 20, size: DefaultMemorySize},
+		{spec: "mem://?bytes=2GB", want: "*store.Memory", bytes: 2 << 30, size: DefaultMemorySize},
+		{spec: "mem://?size=0", wantErr: "invalid limit"},
+		{spec: "mem://?size=5x", wantErr: "invalid limit"},
+		{spec: "mem://?bytes=0", wantErr: "invalid limit"},
+		{spec: "mem://?bytes=muito", wantErr: "invalid limit"},
+		{spec: "mem://?bytes=1.5MB", wantErr: "invalid limit"},
+		{spec: "file://" + dir, want: "*store.File"},
+		{spec: "file://", wantErr: "needs a directory"},
+		{spec: "redis://:bad:port", wantErr: "invalid redis URL"},
+		{spec: "s3://bucket", wantErr: "unknown backend"},
+	}
+	for _, tt := range tests {
+		s, err := Open(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Open(%q) err = %v, want %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Open(%q): %v", tt.spec, err)
+			continue
+		}
+		switch s := s.(type) {
+		case *Memory:
+			if tt.want != "*store.Memory" || s.maxBytes != tt.bytes || s.size != tt.size {
+				t.Errorf("Open(%q) = memory of %d bytes and %d entries, want %s of %d and %d",
+					tt.spec, s.maxBytes, s.size, tt.want, tt.bytes, tt.size)
+			}
+		case *File:
+			if tt.want != "*store.File" {
+				t.Errorf("Open(%q) = file store, want %s", tt.spec, tt.want)
+			}
+		}
+		s.Close()
+	}
+}
+
+// testStore runs the behaviour every backend shares.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	if _, ok, err := s.Get(ctx, "gowork:jobs:a"); ok || err != nil {
+		t.Fatalf("Get on empty store = %v, %v", ok, err)
+	}
+	entries := map[string]string{
+		"gowork:jobs:v2:gupy:1": `{"n":1}`,
+		"gowork:jobs:v2:gupy:2": `{"n":2}`,
+		"gowork:http:abc":       `{"n":3}`,
+	}
+	for k, v := range entries {
+		if err := s.Set(ctx, k, []byte(v), time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Set(ctx, "gowork:jobs:v2:gupy:old", []byte(`{}`), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok, err := s.Get(ctx, "gowork:http:abc")
+	if err != nil || !ok || string(v) != `{"n":3}` {
+		t.Errorf("Get = %s, %v, %v", v, ok, err)
+	}
+	if _, ok, _ := s.Get(ctx, "gowork:jobs:v2:gupy:old"); ok {
+		t.Error("Get returned an expired entry")
+	}
+
+	var keys []string
+	err = s.Scan(ctx, "gowork:jobs:*", func(key string, value []byte, expires time.Time) error {
+		keys = append(keys, key)
+		if value == nil || time.Until(expires) <= 0 {
+			t.Errorf("Scan(%s) = %s expiring %v", key, value, expires)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(keys)
+	if want := []string{"gowork:jobs:v2:gupy:1", "gowork:jobs:v2:gupy:2"}; !slices.Equal(keys, want) {
+		t.Errorf("Scan = %v, want %v", keys, want)
+	}
+
+	if err := s.Delete(ctx, "gowork:jobs:v2:gupy:1", "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Get(ctx, "gowork:jobs:v2:gupy:1"); ok {
+		t.Error("Get returned a deleted entry")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory(DefaultMemoryBytes, 10))
+}
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory(0, 2)
+	s.Set(ctx, "a", []byte("1"), time.Hour)
+	s.Set(ctx, "b", []byte("2"), time.Hour)
+	s.Get(ctx, "a") // "b" passa a ser o menos usado
+	s.Set(ctx, "c", []byte("3"), time.Hour)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := s.Get(ctx, key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestMemoryByteBudget(t *testing.T) {
+	ctx := context.Background()
+	// Cada entrada custa chave + valor: "a" com 9 bytes são 10.
+	s := NewMemory(30, 0)
+	value := func(n int) []byte { return []byte(strings.Repeat("x", n)) }
+	present := func() []string {
+		var keys []string
+		for _, k := range []string{"a", "b", "c", "d", "e"} {
+			if _, ok, _ := s.Get(ctx, k); ok {
+				keys = append(keys, k)
+			}
+		}
+		return keys
+	}
+
+	s.Set(ctx, "a", value(9), time.Hour)
+	s.Set(ctx, "b", value(9), time.Hour)
+	s.Set(ctx, "c", value(9), time.Hour)
+	s.Get(ctx, "a") // "b" passa a ser o menos usado
+	s.Set(ctx, "d", value(9), time.Hour)
+	if got := present(); !slices.Equal(got, []string{"a", "c", "d"}) || s.used != 30 {
+		t.Errorf("after filling: keys %v, %d bytes, want [a c d] and 30", got, s.used)
+	}
+
+	// Uma entrada grande tira várias pequenas.
+	s.Set(ctx, "e", value(19), time.Hour)
+	if got := present(); !slices.Equal(got, []string{"d", "e"}) || s.used != 30 {
+		t.Errorf("after a big entry: keys %v, %d bytes, want [d e] and 30", got, s.used)
+	}
+
+	// Substituir uma entrada ajusta a conta.
+	s.Set(ctx, "e", value(1), time.Hour)
+	if s.used != 12 {
+		t.Errorf("after shrinking e: %d bytes, want 12", s.used)
+	}
+
+	// Maior que o orçamento inteiro: não é guardada e não derruba as outras.
+	s.Set(ctx, "a", value(40), time.Hour)
+	if got := present(); !slices.Equal(got, []string{"d", "e"}) || s.used != 12 {
+		t.Errorf("after an oversized entry: keys %v, %d bytes, want [d e] and 12", got, s.used)
+	}
+
+	s.Delete(ctx, "d")
+	s.Set(ctx, "b", value(1), -time.Second) // já vencida
+	s.Get(ctx, "b")
+	if s.used != 2 || s.order.Len() != 1 {
+		t.Errorf("after delete and expiry: %d bytes in %d entries, want 2 in 1", s.used, s.order.Len())
+	}
+}
+
+func TestFile(t *testing.T) {
+	testStore(t, mustFile(t))
+}
+
+func TestFileNames(t *testing.T) {
+	ctx := context.Background()
+	s := mustFile(t)
+
+	if err := s.Set(ctx, "gowork:jobs:v2:gupy:1", []byte(`[]`), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "gowork_jobs_v2_gupy_1.json")); err != nil {
+		t.Errorf("readable key not stored under its own name: %v", err)
+	}
+
+	// Chaves com "/" ou espaço viram um hash no nome do arquivo.
+	key := "GET https://example.com/a?b=1"
+	if err := s.Set(ctx, key, []byte(`{}`), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Get(ctx, key); !ok {
+		t.Error("hashed key not found")
+	}
+
+	if err := s.Set(ctx, "raw", []byte("not json"), time.Hour); err == nil {
+		t.Error("Set accepted a value that is not JSON")
+	}
+}
+
+func mustFile(t *testing.T) *File {
+	t.Helper()
+	s, err := NewFile(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}